@@ -0,0 +1,192 @@
+/**---------------------------------------------------------
+ * name: async.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Overflow selects what an async Writer does when its queue is full.
+type Overflow int
+
+const (
+	OverflowBlock      Overflow = iota // wait for room in the queue
+	OverflowDropNewest                 // drop the message being written
+	OverflowDropOldest                 // drop the oldest queued message
+)
+
+const (
+	defaultBufferSize    = 64 << 10
+	defaultFlushInterval = time.Second
+)
+
+// WithAsync queues the writes in a bounded queue of size messages and
+// writes them from a background goroutine through a buffer, which is
+// flushed when it is full, at the flush interval, and on Flush, Sync and
+// Close. The overflow policy applies when the queue is full, dropped
+// messages are counted by Dropped. The size must be at least 1.
+func WithAsync(size int, overflow Overflow) Option {
+	return func(c *Writer) {
+		if size < 1 {
+			c.err = fmt.Errorf("log: async queue size %d, must be at least 1", size)
+			return
+		}
+		c.queue = make(chan []byte, size)
+		c.overflow = overflow
+	}
+}
+
+// WithBufferSize sets the number of bytes buffered by an async Writer
+// before they are flushed, the default is 64 KiB.
+func WithBufferSize(size int) Option {
+	return func(c *Writer) {
+		c.bufSize = size
+	}
+}
+
+// WithFlushInterval sets the interval at which an async Writer flushes
+// its buffer, the default is one second.
+func WithFlushInterval(interval time.Duration) Option {
+	return func(c *Writer) {
+		c.interval = interval
+	}
+}
+
+// Flush writes the queued and buffered bytes to the current file.
+func (c *Writer) Flush() error {
+	if c.queue == nil {
+		c.mux.Lock()
+		defer c.mux.Unlock()
+		return c.flushLocked()
+	}
+
+	done := make(chan struct{})
+	select {
+	case c.flushReq <- done:
+		<-done
+		return nil
+	case <-c.stopped:
+		return os.ErrClosed
+	}
+}
+
+// Sync flushes the Writer and commits the current file to stable storage.
+func (c *Writer) Sync() error {
+	if err := c.Flush(); err != nil {
+		return err
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.fp == nil {
+		return nil
+	}
+	return c.fp.Sync()
+}
+
+// Dropped returns the number of messages dropped by the overflow policy.
+func (c *Writer) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+func (c *Writer) start() {
+	if c.bufSize <= 0 {
+		c.bufSize = defaultBufferSize
+	}
+	if c.interval <= 0 {
+		c.interval = defaultFlushInterval
+	}
+	c.flushReq = make(chan chan struct{})
+	c.quit = make(chan struct{})
+	c.stopped = make(chan struct{})
+	go c.run()
+}
+
+func (c *Writer) enqueue(b []byte) (int, error) {
+	if c.closed.Load() {
+		return 0, os.ErrClosed
+	}
+	p := append([]byte(nil), b...)
+
+	switch c.overflow {
+	case OverflowDropNewest:
+		select {
+		case c.queue <- p:
+		default:
+			c.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case c.queue <- p:
+				return len(b), nil
+			default:
+			}
+			select {
+			case <-c.queue:
+				c.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case c.queue <- p:
+		case <-c.quit:
+			return 0, os.ErrClosed
+		}
+	}
+	return len(b), nil
+}
+
+// run writes the queued bytes until the Writer is closed.
+func (c *Writer) run() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case b := <-c.queue:
+			c.mux.Lock()
+			c.writeLocked(b)
+			c.mux.Unlock()
+		case <-ticker.C:
+			c.mux.Lock()
+			if err := c.flushLocked(); err != nil {
+				fmt.Println(err)
+			}
+			c.mux.Unlock()
+		case done := <-c.flushReq:
+			c.drain()
+			close(done)
+		case <-c.quit:
+			c.drain()
+			return
+		}
+	}
+}
+
+// drain writes the queued bytes and flushes the buffer.
+func (c *Writer) drain() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for {
+		select {
+		case b := <-c.queue:
+			c.writeLocked(b)
+		default:
+			if err := c.flushLocked(); err != nil {
+				fmt.Println(err)
+			}
+			return
+		}
+	}
+}
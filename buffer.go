@@ -0,0 +1,107 @@
+/**---------------------------------------------------------
+ * name: buffer.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxPooledBuffer is the capacity above which a buffer is not put back, so
+// one huge line doesn't pin its memory.
+const maxPooledBuffer = 64 << 10
+
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer puts the buffer back, its bytes must not be used afterwards.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufPool.Put(buf)
+}
+
+var recordPool = sync.Pool{
+	New: func() any { return new(Record) },
+}
+
+// encodeRecord encodes a copy of r held in a pooled Record, passing &r to
+// the Encoder interface would move every record to the heap.
+func encodeRecord(enc Encoder, buf *bytes.Buffer, r Record) error {
+	rp := recordPool.Get().(*Record)
+	*rp = r
+	err := enc.Encode(buf, rp)
+	*rp = Record{} // don't keep the fields alive
+	recordPool.Put(rp)
+	return err
+}
+
+// appendPrimitive writes ints, floats, bools, durations, times and errors
+// without going through pretty.Formatter. Numbers and bools are written
+// the way pretty.Formatter does, e.g. int(1), uint(0x1) and float64(1.5).
+// It reports false for the other types.
+func appendPrimitive(buf *bytes.Buffer, value any) bool {
+	var scratch [64]byte
+	b := scratch[:0]
+	switch v := value.(type) {
+	case int:
+		b = strconv.AppendInt(append(b, "int("...), int64(v), 10)
+	case int8:
+		b = strconv.AppendInt(append(b, "int8("...), int64(v), 10)
+	case int16:
+		b = strconv.AppendInt(append(b, "int16("...), int64(v), 10)
+	case int32:
+		b = strconv.AppendInt(append(b, "int32("...), int64(v), 10)
+	case int64:
+		b = strconv.AppendInt(append(b, "int64("...), v, 10)
+	case uint:
+		b = strconv.AppendUint(append(b, "uint(0x"...), uint64(v), 16)
+	case uint8:
+		b = strconv.AppendUint(append(b, "uint8(0x"...), uint64(v), 16)
+	case uint16:
+		b = strconv.AppendUint(append(b, "uint16(0x"...), uint64(v), 16)
+	case uint32:
+		b = strconv.AppendUint(append(b, "uint32(0x"...), uint64(v), 16)
+	case uint64:
+		b = strconv.AppendUint(append(b, "uint64(0x"...), v, 16)
+	case uintptr:
+		b = strconv.AppendUint(append(b, "uintptr(0x"...), uint64(v), 16)
+	case float32:
+		b = strconv.AppendFloat(append(b, "float32("...), float64(v), 'g', -1, 32)
+	case float64:
+		b = strconv.AppendFloat(append(b, "float64("...), v, 'g', -1, 64)
+	case bool:
+		b = strconv.AppendBool(append(b, "bool("...), v)
+	case time.Duration:
+		buf.WriteString(v.String())
+		return true
+	case time.Time:
+		buf.Write(v.AppendFormat(b, time.RFC3339Nano))
+		return true
+	case error:
+		if isNilPointer(v) {
+			return false // Error may dereference it, pretty.Formatter prints (*T)(nil)
+		}
+		buf.WriteString(v.Error())
+		return true
+	default:
+		return false
+	}
+	buf.Write(append(b, ')'))
+	return true
+}
@@ -0,0 +1,95 @@
+/**---------------------------------------------------------
+ * name: buffer_test.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chunqian/tinylog/pretty"
+)
+
+func TestAppendPrimitive(t *testing.T) {
+	// numbers and bools are written like pretty.Formatter does
+	for _, v := range []any{
+		0, -1, int8(-8), int16(16), int32(-32), int64(math.MinInt64),
+		uint(0), uint8(255), uint16(16), uint32(32), uint64(math.MaxUint64), uintptr(0xff),
+		float32(2.25), 1.5, 3.0, 1e21, math.Inf(-1),
+		true, false,
+	} {
+		var buf bytes.Buffer
+		if !appendPrimitive(&buf, v) {
+			t.Errorf("%T not a primitive", v)
+			continue
+		}
+		if want := fmt.Sprintf("%# v", pretty.Formatter(v)); buf.String() != want {
+			t.Errorf("%T: got %q, want %q", v, buf.String(), want)
+		}
+	}
+
+	tm := time.Date(2026, 10, 18, 1, 2, 3, 4, time.UTC)
+	for _, tt := range []struct {
+		v    any
+		want string
+	}{
+		{3 * time.Second, "3s"},
+		{tm, "2026-10-18T01:02:03.000000004Z"},
+		{errors.New("boom"), "boom"},
+	} {
+		var buf bytes.Buffer
+		if !appendPrimitive(&buf, tt.v) || buf.String() != tt.want {
+			t.Errorf("%T: got %q, want %q", tt.v, buf.String(), tt.want)
+		}
+	}
+
+	if appendPrimitive(&bytes.Buffer{}, struct{}{}) {
+		t.Error("struct is a primitive")
+	}
+	if appendPrimitive(&bytes.Buffer{}, error((*nilErr)(nil))) {
+		t.Error("nil *nilErr is a primitive")
+	}
+	if got, want := sprint(ERROR, false, []any{"{}", (*nilErr)(nil)}, nil), "(*log.nilErr)(nil)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func BenchmarkAppendPrimitive(b *testing.B) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	values := []any{42, 3.14, true, time.Second, errors.New("boom")}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		for _, v := range values {
+			appendPrimitive(buf, v)
+		}
+	}
+}
+
+func BenchmarkSprintPrimitives(b *testing.B) {
+	args := []any{"id {} took {} ok {} after {}", 42, 3.14, true, time.Second}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sprint(INFO, false, args, nil)
+	}
+}
+
+// BenchmarkLoggerInfo allocates only the Record.Message string.
+func BenchmarkLoggerInfo(b *testing.B) {
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("id {} took {} ok {}", 42, 3.14, true)
+	}
+}
@@ -0,0 +1,112 @@
+/**---------------------------------------------------------
+ * name: config.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"sync"
+)
+
+// configMux guards the package variables read by the default Logger.
+var configMux sync.RWMutex
+
+// SetPrefix sets Prefix safely while logging.
+func SetPrefix(prefix string) {
+	configMux.Lock()
+	defer configMux.Unlock()
+	Prefix = prefix
+}
+
+// GetPrefix returns Prefix.
+func GetPrefix() string {
+	configMux.RLock()
+	defer configMux.RUnlock()
+	return Prefix
+}
+
+// SetTimeFormat sets TimeFormat safely while logging.
+func SetTimeFormat(format string) {
+	configMux.Lock()
+	defer configMux.Unlock()
+	TimeFormat = format
+}
+
+// GetTimeFormat returns TimeFormat.
+func GetTimeFormat() string {
+	configMux.RLock()
+	defer configMux.RUnlock()
+	return TimeFormat
+}
+
+// SetNonColor sets NonColor safely while logging.
+func SetNonColor(nonColor bool) {
+	configMux.Lock()
+	defer configMux.Unlock()
+	NonColor = nonColor
+}
+
+// GetNonColor returns NonColor.
+func GetNonColor() bool {
+	configMux.RLock()
+	defer configMux.RUnlock()
+	return NonColor
+}
+
+// SetShowDepth sets ShowDepth safely while logging.
+func SetShowDepth(show bool) {
+	configMux.Lock()
+	defer configMux.Unlock()
+	ShowDepth = show
+}
+
+// GetShowDepth returns ShowDepth.
+func GetShowDepth() bool {
+	configMux.RLock()
+	defer configMux.RUnlock()
+	return ShowDepth
+}
+
+// SetShowTime sets ShowTime safely while logging.
+func SetShowTime(show bool) {
+	configMux.Lock()
+	defer configMux.Unlock()
+	ShowTime = show
+}
+
+// GetShowTime returns ShowTime.
+func GetShowTime() bool {
+	configMux.RLock()
+	defer configMux.RUnlock()
+	return ShowTime
+}
+
+// SetShowPrefix sets ShowPrefix safely while logging.
+func SetShowPrefix(show bool) {
+	configMux.Lock()
+	defer configMux.Unlock()
+	ShowPrefix = show
+}
+
+// GetShowPrefix returns ShowPrefix.
+func GetShowPrefix() bool {
+	configMux.RLock()
+	defer configMux.RUnlock()
+	return ShowPrefix
+}
+
+// SetCallerDepth sets DefaultCallerDepth safely while logging.
+func SetCallerDepth(depth int) {
+	configMux.Lock()
+	defer configMux.Unlock()
+	DefaultCallerDepth = depth
+}
+
+// GetCallerDepth returns DefaultCallerDepth.
+func GetCallerDepth() int {
+	configMux.RLock()
+	defer configMux.RUnlock()
+	return DefaultCallerDepth
+}
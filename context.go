@@ -0,0 +1,139 @@
+/**---------------------------------------------------------
+ * name: context.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"context"
+)
+
+type (
+	loggerKey struct{}
+	fieldsKey struct{}
+)
+
+// NewContext returns a copy of ctx carrying the Logger.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx, or the default Logger.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+			return l
+		}
+	}
+	return std
+}
+
+// ContextWithFields returns a copy of ctx carrying the fields after the
+// ones already stored, they are written by the Context variants.
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	old := FieldsFromContext(ctx)
+	all := make([]Field, 0, len(old)+len(fields))
+	all = append(all, old...)
+	all = append(all, fields...)
+	return context.WithValue(ctx, fieldsKey{}, all)
+}
+
+// FieldsFromContext returns the fields stored in ctx.
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]Field)
+	return fields
+}
+
+// DebugContext writes the args and the ctx fields with the DEBUG level.
+func (l *Logger) DebugContext(ctx context.Context, args ...any) {
+	l.PrintContext(ctx, DEBUG, -1, false, args...)
+}
+
+// InfoContext writes the args and the ctx fields with the INFO level.
+func (l *Logger) InfoContext(ctx context.Context, args ...any) {
+	l.PrintContext(ctx, INFO, -1, false, args...)
+}
+
+// WarnContext writes the args and the ctx fields with the WARNING level.
+func (l *Logger) WarnContext(ctx context.Context, args ...any) {
+	l.PrintContext(ctx, WARNING, -1, false, args...)
+}
+
+// ErrorContext writes the args and the ctx fields with the ERROR level.
+func (l *Logger) ErrorContext(ctx context.Context, args ...any) {
+	l.PrintContext(ctx, ERROR, -1, false, args...)
+}
+
+// FatalContext writes the args and the ctx fields with the FATAL level
+// and exits.
+func (l *Logger) FatalContext(ctx context.Context, args ...any) {
+	l.PrintContext(ctx, FATAL, -1, false, args...)
+}
+
+// PanicContext writes the args and the ctx fields with the PANIC level and
+// panics with the message.
+func (l *Logger) PanicContext(ctx context.Context, args ...any) {
+	l.PrintContext(ctx, PANIC, -1, false, args...)
+}
+
+// PrintContext is like Print but writes through the Logger stored in ctx
+// with the ctx fields.
+func PrintContext(ctx context.Context, level Level, depth int, addNewline bool, args ...any) {
+	if depth == -1 {
+		depth = GetCallerDepth()
+	}
+	FromContext(ctx).PrintContext(ctx, level, depth+1, addNewline, args...)
+}
+
+func debugContextD(ctx context.Context, depth int, args ...any) {
+	PrintContext(ctx, DEBUG, depth, false, args...)
+}
+
+func DebugContext(ctx context.Context, args ...any) {
+	debugContextD(ctx, -1, args...)
+}
+
+func infoContextD(ctx context.Context, depth int, args ...any) {
+	PrintContext(ctx, INFO, depth, false, args...)
+}
+
+func InfoContext(ctx context.Context, args ...any) {
+	infoContextD(ctx, -1, args...)
+}
+
+func warnContextD(ctx context.Context, depth int, args ...any) {
+	PrintContext(ctx, WARNING, depth, false, args...)
+}
+
+func WarnContext(ctx context.Context, args ...any) {
+	warnContextD(ctx, -1, args...)
+}
+
+func errorContextD(ctx context.Context, depth int, args ...any) {
+	PrintContext(ctx, ERROR, depth, false, args...)
+}
+
+func ErrorContext(ctx context.Context, args ...any) {
+	errorContextD(ctx, -1, args...)
+}
+
+func fatalContextD(ctx context.Context, depth int, args ...any) {
+	PrintContext(ctx, FATAL, depth, false, args...)
+}
+
+func FatalContext(ctx context.Context, args ...any) {
+	fatalContextD(ctx, -1, args...)
+}
+
+func panicContextD(ctx context.Context, depth int, args ...any) {
+	PrintContext(ctx, PANIC, depth, false, args...)
+}
+
+func PanicContext(ctx context.Context, args ...any) {
+	panicContextD(ctx, -1, args...)
+}
@@ -0,0 +1,115 @@
+/**---------------------------------------------------------
+ * name: dedup.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// deduper collapses consecutive identical records, like syslogd.
+type deduper struct {
+	mu       sync.Mutex
+	timeout  time.Duration
+	last     Record
+	has      bool
+	repeated int
+	timer    *time.Timer
+}
+
+// SetDedup collapses consecutive identical messages, same level, text and
+// fields, into "last message repeated N times". The line is written when a
+// different message arrives or timeout after the first repeat, 0 disables
+// it.
+func (l *Logger) SetDedup(timeout time.Duration) {
+	l.mu.Lock()
+	old := l.deduper
+	if timeout > 0 {
+		l.deduper = &deduper{timeout: timeout}
+	} else {
+		l.deduper = nil
+	}
+	l.mu.Unlock()
+
+	if old != nil {
+		l.flushRepeated(old)
+	}
+}
+
+// dedup reports whether the record is written, the repeat line of the
+// previous record is written first when it differs.
+func (l *Logger) dedup(s settings, r Record) bool {
+	d := s.deduper
+	if d == nil {
+		return true
+	}
+
+	d.mu.Lock()
+	if d.has && r.Level != FATAL && r.Level != PANIC && sameRecord(d.last, r) {
+		d.repeated++
+		if d.timer == nil {
+			d.timer = time.AfterFunc(d.timeout, func() { l.flushRepeated(d) })
+		}
+		d.mu.Unlock()
+		return false
+	}
+	repeat, ok := d.takeRepeated()
+	d.last, d.has = r, true
+	d.mu.Unlock()
+
+	if ok {
+		l.dispatch(s, repeat)
+	}
+	return true
+}
+
+// takeRepeated returns the repeat line of the last record and resets the
+// count, d.mu must be held.
+func (d *deduper) takeRepeated() (Record, bool) {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.repeated == 0 {
+		return Record{}, false
+	}
+	r := d.last
+	r.Time = time.Now()
+	r.Message = fmt.Sprintf("last message repeated %d times", d.repeated)
+	r.Fields = nil
+	d.repeated = 0
+	return r, true
+}
+
+// flushRepeated writes the pending repeat line of d.
+func (l *Logger) flushRepeated(d *deduper) {
+	d.mu.Lock()
+	r, ok := d.takeRepeated()
+	d.mu.Unlock()
+	if ok {
+		l.dispatch(l.snapshot(), r)
+	}
+}
+
+// sameRecord reports whether b renders like a.
+func sameRecord(a, b Record) bool {
+	if a.Level != b.Level || a.Message != b.Message || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Key != b.Fields[i].Key || formatValue(a.Fields[i].Value) != formatValue(b.Fields[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetDedup collapses consecutive identical messages of the default Logger.
+func SetDedup(timeout time.Duration) {
+	std.SetDedup(timeout)
+}
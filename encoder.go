@@ -0,0 +1,144 @@
+/**---------------------------------------------------------
+ * name: encoder.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+)
+
+// An Encoder writes a record as bytes. Encoders are registered by Format
+// with RegisterEncoder or set directly on a Logger with SetEncoder.
+type Encoder interface {
+	Encode(buf *bytes.Buffer, r *Record) error
+}
+
+// The EncoderFunc type is an adapter to allow the use of ordinary
+// functions as encoders.
+type EncoderFunc func(buf *bytes.Buffer, r *Record) error
+
+// Encode calls f(buf, r).
+func (f EncoderFunc) Encode(buf *bytes.Buffer, r *Record) error {
+	return f(buf, r)
+}
+
+// Format names a registered Encoder.
+type Format string
+
+const (
+	TextFormat   Format = "text"   // bracketed, optionally colored text
+	JSONFormat   Format = "json"   // one JSON object per line
+	LogfmtFormat Format = "logfmt" // key=value pairs
+)
+
+var (
+	// TextEncoder writes the bracketed text line, it is the default.
+	TextEncoder Encoder = EncoderFunc(encodeText)
+	// PlainTextEncoder writes the text line without colors, e.g. for files.
+	PlainTextEncoder Encoder = EncoderFunc(func(buf *bytes.Buffer, r *Record) error {
+		plain := *r
+		plain.NonColor = true
+		return encodeText(buf, &plain)
+	})
+	// JSONEncoder writes one JSON object per line.
+	JSONEncoder Encoder = EncoderFunc(encodeJSON)
+	// LogfmtEncoder writes key=value pairs.
+	LogfmtEncoder Encoder = EncoderFunc(encodeLogfmt)
+
+	encodersMux sync.RWMutex
+	encoders    = map[Format]Encoder{
+		TextFormat:   TextEncoder,
+		JSONFormat:   JSONEncoder,
+		LogfmtFormat: LogfmtEncoder,
+	}
+)
+
+// RegisterEncoder makes an Encoder available by format name, registering
+// an existing name replaces it.
+func RegisterEncoder(format Format, enc Encoder) {
+	encodersMux.Lock()
+	defer encodersMux.Unlock()
+	encoders[format] = enc
+}
+
+// LookupEncoder returns the Encoder registered with the format name.
+func LookupEncoder(format Format) (Encoder, bool) {
+	encodersMux.RLock()
+	defer encodersMux.RUnlock()
+	enc, ok := encoders[format]
+	return enc, ok
+}
+
+// levelColors are the ANSI colors of the levels in the text line.
+var levelColors = [...]string{
+	DEBUG:   "\033[34m",
+	INFO:    "\033[36m",
+	WARNING: "\033[33m",
+	ERROR:   "\033[31m",
+	FATAL:   "\033[35m",
+	MESSAGE: "\033[34m",
+	POINTER: "",
+	PANIC:   "\033[35m",
+}
+
+// encodeText writes the record as the bracketed text line, appending to buf
+// directly so the line is built without allocations.
+func encodeText(buf *bytes.Buffer, r *Record) error {
+	var scratch [64]byte
+
+	if r.ShowPrefix {
+		buf.WriteString(r.Prefix)
+		buf.WriteByte(' ')
+	}
+	if r.ShowTime {
+		if !r.NonColor {
+			buf.WriteString("\033[36m")
+		}
+		buf.Write(r.Time.AppendFormat(scratch[:0], r.TimeFormat))
+		if !r.NonColor {
+			buf.WriteString("\033[0m")
+		}
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteByte('[')
+	color := ""
+	if !r.NonColor && int(r.Level) < len(levelColors) {
+		color = levelColors[r.Level]
+	}
+	buf.WriteString(color)
+	buf.WriteString(levelFlags[r.Level])
+	if color != "" {
+		buf.WriteString("\033[0m")
+	}
+	buf.WriteString("] ")
+
+	if r.ShowDepth {
+		if file, line, fnName, ok := r.caller(); ok {
+			buf.WriteByte('[')
+			buf.WriteString(file)
+			buf.WriteByte(':')
+			buf.Write(strconv.AppendInt(scratch[:0], int64(line), 10))
+			buf.WriteByte(' ')
+			buf.WriteString(fnName)
+			buf.WriteString("()] ")
+		}
+	}
+
+	buf.WriteString(r.Message)
+	for i, f := range r.Fields {
+		if i > 0 || r.Message != "" {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(quoteValue(formatValue(f.Value)))
+	}
+	buf.WriteByte('\n')
+	return nil
+}
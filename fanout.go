@@ -0,0 +1,106 @@
+/**---------------------------------------------------------
+ * name: fanout.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"io"
+	"sync"
+)
+
+// A Sink is one output of a Fanout.
+type Sink struct {
+	Writer  io.Writer
+	Level   Level   // minimum level written to the sink
+	Encoder Encoder // nil means TextEncoder
+}
+
+// A Fanout is a Handler that writes each record to every sink whose level
+// it reaches, e.g. colored text on the terminal, JSON to a rotating Writer
+// and only ERROR and above to a separate errors file.
+type Fanout struct {
+	mu       sync.RWMutex
+	handlers []*StreamHandler
+}
+
+var _ Handler = (*Fanout)(nil) // check if object implements interface
+
+// NewFanout returns a Fanout writing to the sinks.
+func NewFanout(sinks ...Sink) *Fanout {
+	f := &Fanout{}
+	for _, s := range sinks {
+		f.Add(s)
+	}
+	return f
+}
+
+// Add adds a sink.
+func (f *Fanout) Add(s Sink) {
+	h := NewStreamHandler(s.Writer, s.Encoder)
+	h.SetLevel(s.Level)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers = append(f.handlers, h)
+}
+
+// Enabled reports whether any sink wants the level.
+func (f *Fanout) Enabled(level Level) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, h := range f.handlers {
+		if h.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle writes the record to the sinks that want its level, a failing
+// sink doesn't stop the others and the first error is returned.
+func (f *Fanout) Handle(r Record) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var first error
+	for _, h := range f.handlers {
+		if !h.Enabled(r.Level) {
+			continue
+		}
+		if err := h.Handle(r); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Sync flushes the sinks that support it.
+func (f *Fanout) Sync() error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var first error
+	for _, h := range f.handlers {
+		if err := h.Sync(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Close closes the sinks that support it, except stdout and stderr.
+func (f *Fanout) Close() error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var first error
+	for _, h := range f.handlers {
+		if err := h.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,108 @@
+/**---------------------------------------------------------
+ * name: fatal.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// SetExitFunc sets the function called after a FATAL message, nil means
+// os.Exit. Tests can set a function that records the code instead.
+func (l *Logger) SetExitFunc(fn func(code int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitFunc = fn
+}
+
+// SetExitCode sets the code passed to the exit function, the default is 1.
+func (l *Logger) SetExitCode(code int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitCode = code
+}
+
+// AddFatalHook adds a function run with the FATAL record before the
+// outputs are closed, hooks run in the order they were added.
+func (l *Logger) AddFatalHook(fn func(r Record)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hooks := make([]func(r Record), 0, len(l.fatalHooks)+1)
+	hooks = append(hooks, l.fatalHooks...)
+	l.fatalHooks = append(hooks, fn)
+}
+
+// exit runs the fatal hooks, syncs and closes the outputs, then calls the
+// exit function.
+func (l *Logger) exit(s settings, r Record) {
+	for _, fn := range s.fatalHooks {
+		fn(r)
+	}
+
+	if !s.replaced && s.out != nil {
+		closeOutput(s.out)
+	}
+	for _, h := range s.handlers {
+		closeOutput(h)
+	}
+
+	exit := s.exitFunc
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(s.exitCode)
+}
+
+// closeOutput syncs and closes v when it supports it.
+func closeOutput(v any) {
+	if err := syncOutput(v); err != nil {
+		fmt.Println(err)
+	}
+	if isStdStream(v) {
+		return
+	}
+	if c, ok := v.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// syncOutput syncs v when it supports it, stdout and stderr are skipped
+// since fsync fails on terminals and pipes.
+func syncOutput(v any) error {
+	if isStdStream(v) {
+		return nil
+	}
+	if s, ok := v.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+func isStdStream(v any) bool {
+	f, ok := v.(*os.File)
+	return ok && (f == os.Stdout || f == os.Stderr)
+}
+
+// SetExitFunc sets the function called after a FATAL message of the
+// default Logger, nil means os.Exit.
+func SetExitFunc(fn func(code int)) {
+	std.SetExitFunc(fn)
+}
+
+// SetExitCode sets the exit code of the default Logger.
+func SetExitCode(code int) {
+	std.SetExitCode(code)
+}
+
+// AddFatalHook adds a function run with the FATAL record of the default
+// Logger before the outputs are closed.
+func AddFatalHook(fn func(r Record)) {
+	std.AddFatalHook(fn)
+}
@@ -0,0 +1,165 @@
+/**---------------------------------------------------------
+ * name: field.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chunqian/tinylog/pretty"
+)
+
+// A Field is a key/value pair attached to a message. Fields passed among
+// the args of a call are not used for the {} placeholders, they are written
+// after the message and can also be referenced by a {key} placeholder.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String returns a Field with a string value.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns a Field with an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 returns a Field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Uint64 returns a Field with an uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 returns a Field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns a Field with a bool value.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration returns a Field with a time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time returns a Field with a time.Time value.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns a Field with the key "error".
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Any returns a Field with an arbitrary value, complex values are rendered
+// with pretty.Formatter.
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String returns the text form of the field value.
+func (f Field) String() string {
+	return formatValue(f.Value)
+}
+
+// splitFields removes the Field values from args and returns them apart.
+func splitFields(args []any) ([]any, []Field) {
+	n := 0
+	for _, arg := range args {
+		if _, ok := arg.(Field); ok {
+			n++
+		}
+	}
+	if n == 0 {
+		return args, nil
+	}
+
+	rest := make([]any, 0, len(args)-n)
+	fields := make([]Field, 0, n)
+	for _, arg := range args {
+		if f, ok := arg.(Field); ok {
+			fields = append(fields, f)
+		} else {
+			rest = append(rest, arg)
+		}
+	}
+	return rest, fields
+}
+
+// formatValue renders scalar values in their plain form and everything
+// else with pretty.Formatter.
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	}
+	if !isNilPointer(value) {
+		switch v := value.(type) {
+		case error:
+			return v.Error()
+		case fmt.Stringer:
+			return v.String()
+		}
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	case reflect.String:
+		return rv.String()
+	}
+	return strings.ReplaceAll(fmt.Sprintf("%# v", pretty.Formatter(value)), "interface {}", "any")
+}
+
+// isNilPointer reports whether value is a nil pointer, calling Error or
+// String on it may panic so it is rendered like any other pointer.
+func isNilPointer(value any) bool {
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// quoteValue quotes s when it can't be read back as a single token.
+func quoteValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+/**---------------------------------------------------------
+ * name: handler.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// A Handler receives the records built by a Logger. Handlers must not
+// modify the record, use Record.Clone to keep a changed copy.
+type Handler interface {
+	// Enabled reports whether the handler wants records of the level.
+	Enabled(level Level) bool
+	// Handle writes or forwards the record.
+	Handle(r Record) error
+}
+
+// The HandlerFunc type is an adapter to allow the use of ordinary
+// functions as handlers. It is enabled for every level.
+type HandlerFunc func(r Record) error
+
+// Enabled returns true.
+func (f HandlerFunc) Enabled(level Level) bool {
+	return true
+}
+
+// Handle calls f(r).
+func (f HandlerFunc) Handle(r Record) error {
+	return f(r)
+}
+
+// A StreamHandler encodes records and writes them to an io.Writer.
+type StreamHandler struct {
+	mu    sync.Mutex
+	w     io.Writer
+	enc   Encoder
+	level atomic.Int32
+}
+
+var _ Handler = (*StreamHandler)(nil) // check if object implements interface
+
+// NewStreamHandler returns a StreamHandler writing to w with the encoder,
+// nil means TextEncoder.
+func NewStreamHandler(w io.Writer, enc Encoder) *StreamHandler {
+	if enc == nil {
+		enc = TextEncoder
+	}
+	return &StreamHandler{w: w, enc: enc}
+}
+
+// SetLevel sets the minimum level written by the handler.
+func (h *StreamHandler) SetLevel(level Level) {
+	h.level.Store(int32(level))
+}
+
+// Enabled reports whether the level is at least the handler level.
+func (h *StreamHandler) Enabled(level Level) bool {
+	return level >= Level(h.level.Load())
+}
+
+// Handle encodes the record and writes it with a single Write call.
+func (h *StreamHandler) Handle(r Record) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := encodeRecord(h.enc, buf, r); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// Sync flushes the writer when it supports it, e.g. an async Writer.
+func (h *StreamHandler) Sync() error {
+	return syncOutput(h.w)
+}
+
+// Close closes the writer when it supports it, stdout and stderr are never
+// closed.
+func (h *StreamHandler) Close() error {
+	if isStdStream(h.w) {
+		return nil
+	}
+	if c, ok := h.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/**---------------------------------------------------------
+ * name: hook.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"fmt"
+)
+
+// A Hook runs a side effect, like counting errors or pushing to an alert
+// queue, for the records of the levels it declares.
+type Hook interface {
+	Levels() []Level
+	Fire(r Record) error
+}
+
+// HookPhase selects whether a hook fires before or after the record is
+// written to the output and the handlers.
+type HookPhase int
+
+const (
+	BeforeWrite HookPhase = iota
+	AfterWrite
+)
+
+// hookErrorsSize is the buffer of the channel returned by HookErrors.
+const hookErrorsSize = 64
+
+// A HookError reports a failed hook.
+type HookError struct {
+	Hook   Hook
+	Record Record
+	Err    error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("log: hook %T failed on %s %q: %v", e.Hook, e.Record.Level, e.Record.Message, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+type funcHook struct {
+	levels []Level
+	fn     func(r Record) error
+}
+
+func (h funcHook) Levels() []Level     { return h.levels }
+func (h funcHook) Fire(r Record) error { return h.fn(r) }
+
+// NewHook returns a Hook calling fn for the levels, no levels means all of
+// them.
+func NewHook(fn func(r Record) error, levels ...Level) Hook {
+	if len(levels) == 0 {
+		levels = make([]Level, len(levelFlags))
+		for i := range levels {
+			levels[i] = Level(i)
+		}
+	}
+	return funcHook{levels: levels, fn: fn}
+}
+
+// AddHook adds a hook fired in the phase for the levels it declares.
+func (l *Logger) AddHook(phase HookPhase, h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hooks := make([]Hook, 0, len(l.hooks[phase])+1)
+	hooks = append(hooks, l.hooks[phase]...)
+	l.hooks[phase] = append(hooks, h)
+}
+
+// HookErrors returns the channel receiving the *HookError of the failed
+// hooks. Errors are printed instead when nobody asked for the channel or
+// when it is full, so they are never lost silently.
+func (l *Logger) HookErrors() <-chan error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.hookErrors == nil {
+		l.hookErrors = make(chan error, hookErrorsSize)
+	}
+	return l.hookErrors
+}
+
+// fire runs the hooks of the phase that declared the record level.
+func (s *settings) fire(phase HookPhase, r Record) {
+	for _, h := range s.hooks[phase] {
+		if !hasLevel(h.Levels(), r.Level) {
+			continue
+		}
+		if err := h.Fire(r); err != nil {
+			s.reportHookError(&HookError{Hook: h, Record: r, Err: err})
+		}
+	}
+}
+
+func (s *settings) reportHookError(err error) {
+	select {
+	case s.hookErrors <- err:
+	default:
+		fmt.Println(err) // nobody listens or the channel is full
+	}
+}
+
+func hasLevel(levels []Level, level Level) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// AddHook adds a hook to the default Logger.
+func AddHook(phase HookPhase, h Hook) {
+	std.AddHook(phase, h)
+}
+
+// HookErrors returns the channel of the failed hooks of the default Logger.
+func HookErrors() <-chan error {
+	return std.HookErrors()
+}
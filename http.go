@@ -0,0 +1,69 @@
+/**---------------------------------------------------------
+ * name: http.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// levelState is the JSON body of the level handler.
+type levelState struct {
+	Level   *Level           `json:"level,omitempty"`
+	Modules map[string]Level `json:"modules,omitempty"`
+}
+
+// LevelHandler returns an http.Handler that reports the minimum level and
+// the module levels of the Logger on GET and sets them on PUT, both with a
+// JSON body like {"level":"WARN","modules":{"github.com/x/y":"DEBUG"}}.
+// A PUT with "modules" replaces all the module levels.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req levelState
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeLevelError(w, http.StatusBadRequest, err)
+				return
+			}
+			if req.Level == nil && req.Modules == nil {
+				writeLevelError(w, http.StatusBadRequest, fmt.Errorf("log: missing level or modules"))
+				return
+			}
+			if req.Level != nil {
+				l.SetLevel(*req.Level)
+			}
+			if req.Modules != nil {
+				l.SetModuleLevels(req.Modules)
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeLevelError(w, http.StatusMethodNotAllowed, fmt.Errorf("log: method %s not allowed", r.Method))
+			return
+		}
+
+		level := l.GetLevel()
+		writeLevelJSON(w, http.StatusOK, levelState{Level: &level, Modules: l.ModuleLevels()})
+	})
+}
+
+// LevelHandler returns an http.Handler for the level of the default Logger.
+func LevelHandler() http.Handler {
+	return std.LevelHandler()
+}
+
+func writeLevelError(w http.ResponseWriter, code int, err error) {
+	writeLevelJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func writeLevelJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,54 @@
+/**---------------------------------------------------------
+ * name: http_test.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLevelHandler(t *testing.T) {
+	l := New()
+	l.SetLevel(INFO)
+	srv := httptest.NewServer(l.LevelHandler())
+	defer srv.Close()
+
+	do := func(method, body string) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, strings.TrimSpace(string(b))
+	}
+
+	if code, body := do(http.MethodGet, ""); code != 200 || body != `{"level":"INFO"}` {
+		t.Errorf("GET: %d %s", code, body)
+	}
+	if code, body := do(http.MethodPut, `{"level":"debug"}`); code != 200 || body != `{"level":"DEBUG"}` {
+		t.Errorf("PUT: %d %s", code, body)
+	}
+	if l.GetLevel() != DEBUG {
+		t.Errorf("level %s", l.GetLevel())
+	}
+	body := `{"level":"WARN","modules":{"github.com/chunqian/tinylog":"DEBUG"}}`
+	if code, got := do(http.MethodPut, body); code != 200 || got != body {
+		t.Errorf("PUT modules: %d %s", code, got)
+	}
+	if code, _ := do(http.MethodPut, `{"level":"LOUD"}`); code != 400 {
+		t.Errorf("PUT bad level: %d", code)
+	}
+	if code, _ := do(http.MethodPost, `{}`); code != 405 {
+		t.Errorf("POST: %d", code)
+	}
+}
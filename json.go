@@ -0,0 +1,120 @@
+/**---------------------------------------------------------
+ * name: json.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const hex = "0123456789abcdef"
+
+// encodeJSON writes the record as one JSON object per line.
+func encodeJSON(buf *bytes.Buffer, r *Record) error {
+	buf.WriteString(`{"time":`)
+	writeJSONString(buf, r.Time.Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSONString(buf, levelFlags[r.Level])
+	if r.ShowPrefix {
+		buf.WriteString(`,"prefix":`)
+		writeJSONString(buf, r.Prefix)
+	}
+	if file, line, fnName, ok := r.caller(); ok {
+		buf.WriteString(`,"caller":`)
+		writeJSONString(buf, file+":"+strconv.Itoa(line))
+		buf.WriteString(`,"func":`)
+		writeJSONString(buf, fnName)
+	}
+	buf.WriteString(`,"msg":`)
+	writeJSONString(buf, r.Message)
+	for _, f := range r.Fields {
+		buf.WriteByte(',')
+		writeJSONString(buf, f.Key)
+		buf.WriteByte(':')
+		writeJSONValue(buf, f.Value)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// writeJSONValue writes scalars natively and falls back to the text form
+// for values encoding/json can't handle.
+func writeJSONValue(buf *bytes.Buffer, value any) {
+	if value == nil || isNilPointer(value) {
+		buf.WriteString("null")
+		return
+	}
+	switch v := value.(type) {
+	case string:
+		writeJSONString(buf, v)
+		return
+	case error:
+		writeJSONString(buf, v.Error())
+		return
+	case time.Duration:
+		writeJSONString(buf, v.String())
+		return
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		buf.WriteString(formatValue(v))
+		return
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		writeJSONString(buf, formatValue(value))
+		return
+	}
+	buf.Write(b)
+}
+
+// writeJSONString writes s as a quoted JSON string without escaping HTML.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteString(`�`)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
@@ -7,20 +7,16 @@
 package log
 
 import (
-	"bytes"
 	"fmt"
-	"os"
-	"path/filepath"
-	"regexp"
+	"io"
 	"runtime"
 	"runtime/debug"
+	"strconv"
 	"strings"
-	"time"
-	"unsafe"
-
-	"github.com/chunqian/tinylog/pretty"
 )
 
+// The layout of the default Logger. Assigning them while other goroutines
+// log is a data race, use SetPrefix, SetShowTime and alike instead.
 var (
 	Prefix     = "[Log]"
 	TimeFormat = "06-01-02 15:04:05"
@@ -31,9 +27,7 @@ var (
 	ShowPrefix         bool
 	DefaultCallerDepth = 3
 
-	levelFlags = []string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL", "MESSAGE", "POINTER"}
-
-	logWriter *Writer
+	levelFlags = []string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL", "MESSAGE", "POINTER", "PANIC"}
 )
 
 func init() {
@@ -45,6 +39,8 @@ func init() {
 	ShowPrefix = false
 }
 
+// Level is the severity of a message. MESSAGE, POINTER and PANIC sort above
+// FATAL, so they are still written when a minimum level is set.
 type Level int
 
 const (
@@ -55,191 +51,110 @@ const (
 	FATAL
 	MESSAGE
 	POINTER
+	PANIC
 )
 
-func SetOutput(writer *Writer) error {
-	logWriter = writer
-	return nil
-}
-
-func expandToFront(args ...any) []any {
-	top := len(args)
-	args = append(args, 0)
-	copy(args[1:], args[:])
-	var fmtStr = ""
-	for i := 0; i < top; i++ {
-		if i == top-1 {
-			fmtStr += "{}"
-		} else {
-			fmtStr += "{}, "
-		}
+// String returns the name of the level as written in the lines.
+func (l Level) String() string {
+	if l < DEBUG || int(l) >= len(levelFlags) {
+		return "Level(" + strconv.Itoa(int(l)) + ")"
 	}
-	args[0] = fmtStr
-	return args
+	return levelFlags[l]
 }
 
-func expandToEnd(args ...any) []any {
-	var count = 0
-	switch args[0].(type) {
-	case string:
-		s := strings.Split(args[0].(string), "{}")
-		count = len(s) - 1
+// ParseLevel returns the level of a name, case insensitive. WARNING is
+// accepted for WARN.
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "WARNING" {
+		return WARNING, nil
 	}
-
-	var diff_count = 0
-	if len(args)-1 < count {
-		diff_count = count - (len(args) - 1)
-	}
-	for i := 0; i < diff_count; i++ {
-		args = append(args, "not found!")
+	for i, flag := range levelFlags {
+		if flag == name {
+			return Level(i), nil
+		}
 	}
-	return args
+	return DEBUG, fmt.Errorf("log: unknown level %q", name)
 }
 
-func Print(level Level, depth int, addNewline bool, args ...any) {
-	var buf bytes.Buffer
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
 
-	top := len(args)
-	if top == 0 {
-		return
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
 	}
+	*l = level
+	return nil
+}
 
-	switch args[0].(type) {
-	case string:
-		matched, _ := regexp.MatchString(`{}`, args[0].(string))
-		if !matched {
-			args = expandToFront(args...)
-		} else {
-			args = expandToEnd(args...)
-		}
-		top = len(args)
-	default:
-		args = expandToFront(args...)
-		top = len(args)
-	}
+// SetLevel sets the minimum level of the default Logger, messages below it
+// are dropped before any formatting is done.
+func SetLevel(level Level) {
+	std.SetLevel(level)
+}
 
-	var format string
-	var formatSlice []string
+// GetLevel returns the minimum level of the default Logger.
+func GetLevel() Level {
+	return std.GetLevel()
+}
 
-	// Add all the string arguments to the buffer
-	for i := 0; i < top; i++ {
-		var value = args[i]
-		if i == 0 {
-			format = value.(string)
-			formatSlice = strings.Split(format, "{}")
-		}
-		if i >= len(formatSlice) {
-			break
-		}
+// Enabled reports whether the level would be written by the default Logger.
+func Enabled(level Level) bool {
+	return std.Enabled(level)
+}
 
-		if i > 0 {
-			var mStr = ""
-			if level == MESSAGE {
-				if v, ok := value.(*int8); ok {
-					value = pretty.Gostring(v)
-				}
-				if v, ok := value.(*uint8); ok {
-					value = pretty.Gostring(v)
-				}
-				if v, ok := value.(unsafe.Pointer); ok {
-					value = pretty.Gostring2(v)
-				}
-			}
-			if level == POINTER {
-				value = fmt.Sprintf("%p", value)
-			}
-			switch value.(type) {
-			case string:
-				mStr = strings.ReplaceAll(value.(string), "interface {}", "any")
-			default:
-				mStr = strings.ReplaceAll(fmt.Sprintf("%# v", pretty.Formatter(value)), "interface {}", "any")
-			}
-			buf.WriteString(mStr)
-		}
-		buf.WriteString(formatSlice[i])
-	}
-	if addNewline {
-		buf.WriteString("\n")
-	}
+// With returns a child of the default Logger that writes the fields with
+// every message.
+func With(fields ...Field) *Logger {
+	return std.With(fields...)
+}
 
-	var depthInfo string
-	if ShowDepth {
-		if depth == -1 {
-			depth = DefaultCallerDepth
-		}
-		pc, file, line, ok := runtime.Caller(depth)
-		if ok {
-			// Get caller function name.
-			fn := runtime.FuncForPC(pc)
-			var fnName string
-			if fn == nil {
-				fnName = "?()"
-			} else {
-				fnName = strings.TrimLeft(filepath.Ext(fn.Name()), ".") + "()"
-			}
-			depthInfo = fmt.Sprintf("[%s:%d %s] ", filepath.Base(file), line, fnName)
-		}
-	}
+// SetFormat sets the registered Encoder used by the default Logger.
+func SetFormat(format Format) error {
+	return std.SetFormat(format)
+}
 
-	var formatBuf bytes.Buffer
-	var selected []any
+// SetEncoder sets the Encoder used by the default Logger.
+func SetEncoder(enc Encoder) {
+	std.SetEncoder(enc)
+}
 
-	if ShowPrefix {
-		formatBuf.WriteString("%s ")
-		selected = append(selected, Prefix)
-	}
-	if ShowTime {
-		if NonColor {
-			formatBuf.WriteString("%s ")
-		} else {
-			formatBuf.WriteString("\033[36m%s\033[0m ")
-		}
-		selected = append(selected, time.Now().Format(TimeFormat))
-	}
+// SetHandler replaces the output of the default Logger with the handlers,
+// calling it without handlers restores the output.
+func SetHandler(handlers ...Handler) {
+	std.SetHandler(handlers...)
+}
 
-	if NonColor {
-		formatBuf.WriteString("[%s] ")
-		selected = append(selected, levelFlags[level])
-	} else {
-		switch level {
-		case DEBUG:
-			formatBuf.WriteString("[\033[34m%s\033[0m] ")
-		case INFO:
-			formatBuf.WriteString("[\033[36m%s\033[0m] ")
-		case WARNING:
-			formatBuf.WriteString("[\033[33m%s\033[0m] ")
-		case ERROR:
-			formatBuf.WriteString("[\033[31m%s\033[0m] ")
-		case FATAL:
-			formatBuf.WriteString("[\033[35m%s\033[0m] ")
-		case MESSAGE:
-			formatBuf.WriteString("[\033[34m%s\033[0m] ")
-		default:
-			formatBuf.WriteString("[%s] ")
-		}
-		selected = append(selected, levelFlags[level])
-	}
+// AddHandler adds a handler to the default Logger.
+func AddHandler(h Handler) {
+	std.AddHandler(h)
+}
 
-	if ShowDepth {
-		formatBuf.WriteString("%s")
-		selected = append(selected, depthInfo)
-	}
+// SetOutput sets the output of the default Logger, nil means stdout.
+func SetOutput(w io.Writer) error {
+	return std.SetOutput(w)
+}
 
-	formatBuf.WriteString("%s")
-	selected = append(selected, buf.String())
-	formatBuf.WriteString("\n")
+// Sync flushes the output and the handlers of the default Logger.
+func Sync() error {
+	return std.Sync()
+}
 
-	if logWriter != nil {
-		logWriter.Write([]byte(fmt.Sprintf(formatBuf.String(), selected...)))
-	} else {
-		fmt.Printf(formatBuf.String(), selected...)
+// Print formats the args and writes them with the given level through the
+// default Logger. The depth -1 means DefaultCallerDepth.
+func Print(level Level, depth int, addNewline bool, args ...any) {
+	if std.dropped(level) {
+		return
 	}
-
-	if level == FATAL {
-		os.Exit(1)
+	if depth == -1 {
+		depth = GetCallerDepth()
 	}
-
-	return
+	std.Print(level, depth+1, addNewline, args...)
 }
 
 func debugD(depth int, args ...any) {
@@ -282,6 +197,14 @@ func Fatal(args ...any) {
 	fatalD(-1, args...)
 }
 
+func panicD(depth int, args ...any) {
+	Print(PANIC, depth, false, args...)
+}
+
+func Panic(args ...any) {
+	panicD(-1, args...)
+}
+
 func messageD(depth int, args ...any) {
 	Print(MESSAGE, depth, false, args...)
 }
@@ -0,0 +1,645 @@
+/**---------------------------------------------------------
+ * name: log_test.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLogger(t *testing.T) (*Logger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.log")
+	w, err := NewWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(w)
+	return l, path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestLoggers(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+	l1, l2 := New(), New()
+	l1.SetOutput(&buf1)
+	l1.SetNonColor(true)
+	l1.SetPrefix("[One]")
+	l1.SetShowPrefix(true)
+	l2.SetOutput(&buf2)
+	l2.SetNonColor(true)
+	l2.SetLevel(ERROR)
+
+	l1.Info("one")
+	l2.Info("dropped")
+	l2.Error("two")
+	if got := buf1.String(); got != "[One] [INFO] one\n" {
+		t.Errorf("l1 got %q", got)
+	}
+	if got := buf2.String(); got != "[ERROR] two\n" {
+		t.Errorf("l2 got %q", got)
+	}
+
+	// the package functions write through Default
+	defer SetOutput(nil)
+	defer SetNonColor(GetNonColor())
+	defer SetShowTime(GetShowTime())
+	defer SetShowPrefix(GetShowPrefix())
+	var buf bytes.Buffer
+	Default().SetOutput(&buf)
+	SetNonColor(true)
+	SetShowTime(false)
+	SetShowPrefix(false)
+	Warn("default {}", 1)
+	if got := buf.String(); got != "[WARN] default int(1)\n" {
+		t.Errorf("default got %q", got)
+	}
+	if buf1.String() != "[One] [INFO] one\n" {
+		t.Error("default Logger wrote to l1")
+	}
+}
+
+func TestLevel(t *testing.T) {
+	l, path := newTestLogger(t)
+	l.SetLevel(WARNING)
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn {}", "on")
+	l.Message("message")
+
+	want := "[WARN] warn on\n[MESSAGE] message\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	l := New()
+	l.SetLevel(WARNING)
+	if l.GetLevel() != WARNING {
+		t.Errorf("level %s", l.GetLevel())
+	}
+	for level, want := range map[Level]bool{DEBUG: false, INFO: false, WARNING: true, ERROR: true} {
+		if got := l.Enabled(level); got != want {
+			t.Errorf("Enabled(%s) = %v, want %v", level, got, want)
+		}
+	}
+
+	defer SetLevel(GetLevel())
+	SetLevel(ERROR)
+	if GetLevel() != ERROR || Default().GetLevel() != ERROR {
+		t.Errorf("package level %s", GetLevel())
+	}
+	if Enabled(WARNING) || !Enabled(ERROR) {
+		t.Error("package Enabled doesn't follow SetLevel")
+	}
+}
+
+func TestFatal(t *testing.T) {
+	l, path := newTestLogger(t)
+	var code int
+	var hooked string
+	l.SetExitFunc(func(c int) { code = c })
+	l.SetExitCode(3)
+	l.SetLevel(ERROR + 10) // FATAL is never skipped
+	l.AddFatalHook(func(r Record) { hooked = r.Message })
+	l.Fatal("bye {}", "now")
+
+	if code != 3 {
+		t.Errorf("exit code %d, want 3", code)
+	}
+	if hooked != "bye now" {
+		t.Errorf("hook got %q", hooked)
+	}
+	if got := readFile(t, path); got != "[FATAL] bye now\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	l, path := newTestLogger(t)
+	defer func() {
+		if v := recover(); v != "oops int(1)" {
+			t.Errorf("recovered %v", v)
+		}
+		if got := readFile(t, path); !strings.HasPrefix(got, "[PANIC] oops int(1)") {
+			t.Errorf("got %q", got)
+		}
+	}()
+	l.Panic("oops {}", 1)
+}
+
+func TestHandlers(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+
+	var records []Record
+	l.AddHandler(HandlerFunc(func(r Record) error {
+		records = append(records, r)
+		return nil
+	}))
+	_, _, line, _ := runtime.Caller(0)
+	l.With(String("req", "r1")).Info("Say: {}", "Hello", Int("id", 1))
+
+	if got := buf.String(); got != "[INFO] Say: Hello req=r1 id=1\n" {
+		t.Errorf("output got %q", got)
+	}
+	if len(records) != 1 {
+		t.Fatalf("handler got %d records", len(records))
+	}
+	r := records[0]
+	if r.Level != INFO || r.Message != "Say: Hello" || len(r.Fields) != 2 || r.Fields[0].Key != "req" || r.Fields[1].Key != "id" {
+		t.Errorf("record %+v", r)
+	}
+	if frame := r.Frame(); filepath.Base(frame.File) != "log_test.go" || frame.Line != line+1 {
+		t.Errorf("record caller %s:%d, want log_test.go:%d", frame.File, frame.Line, line+1)
+	}
+
+	// SetHandler replaces the output, without handlers it is restored
+	buf.Reset()
+	records = nil
+	var replaced int
+	l.SetHandler(HandlerFunc(func(r Record) error {
+		replaced++
+		return nil
+	}))
+	l.Info("replaced")
+	if buf.Len() != 0 || replaced != 1 || len(records) != 0 {
+		t.Errorf("replaced: output %q, handler %d, added %d", buf.String(), replaced, len(records))
+	}
+	l.SetHandler()
+	l.Info("restored")
+	if got := buf.String(); got != "[INFO] restored\n" || replaced != 1 {
+		t.Errorf("restored: output %q, handler %d", got, replaced)
+	}
+}
+
+func TestFanout(t *testing.T) {
+	var text, json, errs bytes.Buffer
+	l := New()
+	l.SetHandler(NewFanout(
+		Sink{Writer: &text, Encoder: PlainTextEncoder},
+		Sink{Writer: &json, Level: INFO, Encoder: JSONEncoder},
+		Sink{Writer: &errs, Level: ERROR, Encoder: LogfmtEncoder},
+	))
+	l.Debug("debug")
+	l.Info("info")
+	l.Error("error")
+
+	if got := text.String(); got != "[DEBUG] debug\n[INFO] info\n[ERROR] error\n" {
+		t.Errorf("text got %q", got)
+	}
+	if got := strings.Count(json.String(), "\n"); got != 2 || !strings.Contains(json.String(), `"msg":"info"`) {
+		t.Errorf("json got %q", json.String())
+	}
+	if got := errs.String(); !strings.Contains(got, "level=ERROR") || strings.Count(got, "\n") != 1 {
+		t.Errorf("errors got %q", got)
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+	l.Info("to {}", "buffer")
+	if got := buf.String(); got != "[INFO] to buffer\n" {
+		t.Errorf("got %q", got)
+	}
+
+	var w *Writer
+	l.SetOutput(w) // a nil *Writer means stdout
+	if l.snapshot().out != nil {
+		t.Error("nil *Writer kept as output")
+	}
+}
+
+func TestConfigRace(t *testing.T) {
+	defer SetOutput(nil)
+	defer SetShowTime(GetShowTime())
+	defer SetPrefix(GetPrefix())
+	defer SetShowPrefix(GetShowPrefix())
+	var buf bytes.Buffer
+	SetOutput(&buf)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetShowTime(i%2 == 0)
+			SetPrefix("[" + strconv.Itoa(i) + "]")
+			Default().SetShowPrefix(i%3 == 0)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		Info("line {}", i)
+	}
+	<-done
+}
+
+// logWrapped logs through a wrapper frame with the default Logger method
+// and the package function.
+func logWrapped(msg string) {
+	Default().Info(msg)
+	Info(msg)
+}
+
+func TestCallerDepth(t *testing.T) {
+	defer SetOutput(nil)
+	defer SetNonColor(GetNonColor())
+	defer SetShowDepth(GetShowDepth())
+	defer SetShowTime(GetShowTime())
+	defer SetShowPrefix(GetShowPrefix())
+	defer SetCallerDepth(GetCallerDepth())
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetNonColor(true)
+	SetShowDepth(true)
+	SetShowTime(false)
+	SetShowPrefix(false)
+
+	// both report the caller of the wrapper
+	Default().SetCallerDepth(3)
+	if got := GetCallerDepth(); got != 4 {
+		t.Errorf("DefaultCallerDepth %d, want 4", got)
+	}
+	_, _, line, _ := runtime.Caller(0)
+	logWrapped("wrapped")
+
+	want := strings.Repeat(fmt.Sprintf("[INFO] [log_test.go:%d TestCallerDepth()] wrapped\n", line+1), 2)
+	if got := buf.String(); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestModuleLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+	l.SetLevel(WARNING)
+
+	l.SetModuleLevel("github.com/chunqian/tinylog", DEBUG)
+	l.Debug("package")
+	l.SetModuleLevel("github.com/chunqian/tinylog", ERROR)
+	l.Warn("dropped")
+	l.SetModuleLevel("github.com/chunqian", DEBUG) // the longest module wins
+	l.Warn("dropped")
+	l.RemoveModuleLevel("github.com/chunqian/tinylog")
+	l.Info("parent")
+
+	db := l.Named("db")
+	l.SetModuleLevels(map[string]Level{"db": ERROR, "db.query": DEBUG})
+	db.Warn("dropped")
+	db.Named("query").Debug("named")
+	l.Info("dropped")
+
+	want := "[DEBUG] package\n[INFO] parent\n[DEBUG] named\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := packagePath("github.com/chunqian/tinylog.(*Logger).Info"); got != "github.com/chunqian/tinylog" {
+		t.Errorf("packagePath %q", got)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+
+	var before, after []string
+	l.AddHook(BeforeWrite, NewHook(func(r Record) error {
+		before = append(before, r.Message+"|"+buf.String())
+		return nil
+	}, ERROR))
+	l.AddHook(AfterWrite, NewHook(func(r Record) error {
+		after = append(after, r.Message)
+		return errors.New("queue down")
+	}, ERROR, FATAL))
+	errs := l.HookErrors()
+
+	l.Info("info")
+	l.Error("error")
+
+	if len(before) != 1 || before[0] != "error|[INFO] info\n" {
+		t.Errorf("before %q", before)
+	}
+	if len(after) != 1 || after[0] != "error" {
+		t.Errorf("after %q", after)
+	}
+	select {
+	case err := <-errs:
+		var hookErr *HookError
+		if !errors.As(err, &hookErr) || hookErr.Record.Message != "error" || hookErr.Err.Error() != "queue down" {
+			t.Errorf("hook error %v", err)
+		}
+	default:
+		t.Error("no hook error")
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+	l.SetSampling(&Sampling{Interval: time.Hour, First: 2, Thereafter: 3})
+
+	for i := 0; i < 8; i++ {
+		l.Error("failed {}", i)
+	}
+	l.Info("other")
+	l.Sync()
+
+	want := "[ERROR] failed int(0)\n" +
+		"[ERROR] failed int(1)\n" +
+		"[ERROR] failed int(4)\n" +
+		"[ERROR] failed int(7)\n" +
+		"[INFO] other\n" +
+		"[WARN] suppressed 4 messages\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSamplingDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+	l.SetSampling(&Sampling{First: 1}) // the interval defaults to a second
+	l.SetRateLimit(100, 100)
+
+	for i := 0; i < 5; i++ {
+		l.Error("failed")
+	}
+	l.SetRateLimit(0, 0)
+	if l.snapshot().limiter == nil {
+		t.Error("limiter removed while sampling")
+	}
+	l.SetSampling(nil) // both off, the pending summary is written
+	if l.snapshot().limiter != nil {
+		t.Error("limiter kept")
+	}
+
+	want := "[ERROR] failed\n[WARN] suppressed 4 messages\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	tm := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	setNow(t, tm)
+
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+	l.SetRateLimit(1, 2)
+
+	l.Info("a")
+	l.Info("b")
+	l.Info("c")
+	setNow(t, tm.Add(time.Second))
+	l.Info("d")
+	l.Info("e")
+	l.Sync()
+
+	want := "[INFO] a\n[INFO] b\n[INFO] d\n[WARN] suppressed 2 messages\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+	l.SetDedup(time.Hour)
+
+	l.Error("disk full")
+	l.Error("disk full")
+	l.Error("disk full")
+	l.Warn("disk full")
+	l.Warn("disk full", String("dev", "sda"))
+	l.Warn("disk full", String("dev", "sda"))
+	l.Sync()
+
+	want := "[ERROR] disk full\n" +
+		"[ERROR] last message repeated 2 times\n" +
+		"[WARN] disk full\n" +
+		"[WARN] disk full dev=sda\n" +
+		"[WARN] last message repeated 1 times\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+type nilErr struct{ msg string }
+
+func (e *nilErr) Error() string  { return e.msg }
+func (e *nilErr) String() string { return e.msg }
+
+func TestFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+
+	child := l.With(String("req", "a b"))
+	child.Info("Say: {}", Int("id", 1), "Hello", Bool("ok", true))
+	child.With(Duration("took", time.Second)).Warn(Err(errors.New("boom")))
+	l.Info("nil", Err((*nilErr)(nil)), Any("k", (*nilErr)(nil)))
+
+	want := "[INFO] Say: Hello req=\"a b\" id=1 ok=true\n" +
+		"[WARN] req=\"a b\" took=1s error=boom\n" +
+		"[INFO] nil error=(*log.nilErr)(nil) k=(*log.nilErr)(nil)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	args, fields := splitFields([]any{"a", Int("n", 1), 2})
+	if len(args) != 2 || len(fields) != 1 || fields[0].Key != "n" {
+		t.Errorf("splitFields: %v %v", args, fields)
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	r := Record{
+		Time:    time.Date(2026, 10, 18, 1, 2, 3, 0, time.UTC),
+		Level:   ERROR,
+		Message: "say \"hi\"\n\t<b>\x01 \xff",
+		Fields: []Field{
+			Int("id", 1),
+			Bool("ok", true),
+			Float64("nan", math.NaN()),
+			Duration("took", time.Second),
+			Err((*nilErr)(nil)),
+			Any("list", []int{1, 2}),
+			String("k\"ey", "v"),
+		},
+	}
+	var buf bytes.Buffer
+	if err := JSONEncoder.Encode(&buf, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"time":"2026-10-18T01:02:03Z","level":"ERROR","msg":"say \"hi\"\n\t<b>\u0001 �",` +
+		`"id":1,"ok":true,"nan":"NaN","took":"1s","error":null,"list":[1,2],"k\"ey":"v"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("invalid JSON %s", buf.String())
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	r := Record{
+		Time:       time.Date(2026, 10, 18, 1, 2, 3, 0, time.UTC),
+		TimeFormat: "06-01-02 15:04:05",
+		Level:      INFO,
+		Message:    "Say: Hello",
+		Fields: []Field{
+			Int("id", 1),
+			String("empty", ""),
+			String("eq", "a=b"),
+			String("quote", `say "hi"`),
+			String("nl", "a\nb"),
+			String("plain", "tiny"),
+			String("bad key=", "v"),
+			String("", "v"),
+		},
+	}
+	var buf bytes.Buffer
+	if err := LogfmtEncoder.Encode(&buf, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `time="26-10-18 01:02:03" level=INFO msg="Say: Hello" id=1 empty="" eq="a=b" ` +
+		`quote="say \"hi\"" nl="a\nb" plain=tiny bad_key_=v _=v` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetOutput(&buf)
+
+	if err := l.SetFormat("test-short"); err == nil {
+		t.Error("unknown format accepted")
+	}
+	t.Cleanup(func() {
+		encodersMux.Lock()
+		defer encodersMux.Unlock()
+		delete(encoders, "test-short")
+	})
+	RegisterEncoder("test-short", EncoderFunc(func(buf *bytes.Buffer, r *Record) error {
+		buf.WriteString(r.Level.String() + " " + r.Message + "\n")
+		return nil
+	}))
+	if _, ok := LookupEncoder("test-short"); !ok {
+		t.Fatal("encoder not registered")
+	}
+	if err := l.SetFormat("test-short"); err != nil {
+		t.Fatal(err)
+	}
+	l.Warn("Say: {}", "Hello")
+
+	if got, want := buf.String(), "WARN Say: Hello\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestContext(t *testing.T) {
+	defer SetOutput(nil)
+	defer SetNonColor(GetNonColor())
+	defer SetShowDepth(GetShowDepth())
+	defer SetShowTime(GetShowTime())
+	defer SetShowPrefix(GetShowPrefix())
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetNonColor(true)
+	SetShowDepth(true)
+	SetShowTime(false)
+	SetShowPrefix(false)
+
+	if FromContext(context.Background()) != Default() {
+		t.Error("FromContext doesn't fall back to the default Logger")
+	}
+	l := New()
+	if FromContext(NewContext(context.Background(), l)) != l {
+		t.Error("FromContext doesn't return the stored Logger")
+	}
+
+	ctx := ContextWithFields(context.Background(), String("req", "r1"))
+	ctx = ContextWithFields(ctx, String("trace", "t1"))
+	_, _, line, _ := runtime.Caller(0)
+	InfoContext(ctx, "Say: {}", "Hello")
+	Default().WarnContext(ctx, "done")
+
+	want := fmt.Sprintf("[INFO] [log_test.go:%d TestContext()] Say: Hello req=r1 trace=t1\n", line+1) +
+		fmt.Sprintf("[WARN] [log_test.go:%d TestContext()] done req=r1 trace=t1\n", line+2)
+	if got := buf.String(); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestTextEncoder(t *testing.T) {
+	r := Record{
+		Time:       time.Date(2026, 10, 18, 1, 2, 3, 0, time.UTC),
+		TimeFormat: "06-01-02 15:04:05",
+		Level:      ERROR,
+		Prefix:     "[Log]",
+		Message:    "Say: Hello",
+		Fields:     []Field{Int("id", 1), String("user", "a b")},
+		ShowTime:   true,
+		ShowPrefix: true,
+	}
+	var buf bytes.Buffer
+	TextEncoder.Encode(&buf, &r)
+	PlainTextEncoder.Encode(&buf, &r)
+	r.Level, r.Message, r.ShowTime, r.ShowPrefix = POINTER, "", false, false
+	TextEncoder.Encode(&buf, &r)
+
+	want := "[Log] \033[36m26-10-18 01:02:03\033[0m [\033[31mERROR\033[0m] Say: Hello id=1 user=\"a b\"\n" +
+		"[Log] 26-10-18 01:02:03 [ERROR] Say: Hello id=1 user=\"a b\"\n" +
+		"[POINTER] id=1 user=\"a b\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
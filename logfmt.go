@@ -0,0 +1,58 @@
+/**---------------------------------------------------------
+ * name: logfmt.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// encodeLogfmt writes the record as key=value pairs, quoting the values
+// that contain spaces, quotes, '=' or control characters.
+func encodeLogfmt(buf *bytes.Buffer, r *Record) error {
+	writeLogfmtPair(buf, "time", r.Time.Format(r.TimeFormat))
+	buf.WriteByte(' ')
+	writeLogfmtPair(buf, "level", levelFlags[r.Level])
+	if r.ShowPrefix {
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, "prefix", r.Prefix)
+	}
+	if file, line, fnName, ok := r.caller(); ok {
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, "caller", file+":"+strconv.Itoa(line))
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, "func", fnName)
+	}
+	buf.WriteByte(' ')
+	writeLogfmtPair(buf, "msg", r.Message)
+	for _, f := range r.Fields {
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, f.Key, formatValue(f.Value))
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+func writeLogfmtPair(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(logfmtKey(key))
+	buf.WriteByte('=')
+	buf.WriteString(quoteValue(value))
+}
+
+// logfmtKey drops the characters that are not allowed in a key.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, key)
+}
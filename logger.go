@@ -0,0 +1,526 @@
+/**---------------------------------------------------------
+ * name: logger.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A Logger writes leveled messages with its own prefix, time format,
+// flags, level threshold and output. A Logger can be used simultaneously
+// from multiple goroutines.
+type Logger struct {
+	*core
+	fields []Field // attached by With
+	name   string  // set by Named, matched by the module levels
+}
+
+// core is the configuration shared by a Logger and the loggers derived
+// from it with With.
+type core struct {
+	mu sync.RWMutex
+
+	prefix      string
+	timeFormat  string
+	nonColor    bool
+	showDepth   bool
+	showTime    bool
+	showPrefix  bool
+	callerDepth int
+	level       atomic.Int32 // minimum level, read without the lock
+	modules     atomic.Pointer[moduleLevels]
+	encoder     Encoder   // nil means TextEncoder
+	out         io.Writer // nil means stdout
+	outMux      sync.Mutex
+	handlers    []Handler
+	replaced    bool // if true, the handlers replace the output
+	exitFunc    func(code int)
+	exitCode    int
+	fatalHooks  []func(r Record)
+	hooks       [2][]Hook // by HookPhase
+	hookErrors  chan error
+	limiter     *limiter // set by SetSampling and SetRateLimit
+	deduper     *deduper // set by SetDedup
+
+	std bool // if true, layout settings are read from the package variables
+}
+
+// settings is a snapshot of the Logger configuration taken once per call.
+type settings struct {
+	prefix     string
+	timeFormat string
+	nonColor   bool
+	showDepth  bool
+	showTime   bool
+	showPrefix bool
+	encoder    Encoder
+	out        io.Writer
+	handlers   []Handler
+	replaced   bool
+	exitFunc   func(code int)
+	exitCode   int
+	fatalHooks []func(r Record)
+	hooks      [2][]Hook
+	hookErrors chan error
+	limiter    *limiter
+	deduper    *deduper
+}
+
+// std is the default Logger used by the package level functions.
+var std = &Logger{core: &core{callerDepth: 2, exitCode: 1, std: true}}
+
+// New returns a Logger with the default settings that writes to stdout.
+func New() *Logger {
+	return &Logger{
+		core: &core{
+			prefix:      "[Log]",
+			timeFormat:  "06-01-02 15:04:05",
+			nonColor:    runtime.GOOS == "windows",
+			callerDepth: 2,
+			exitCode:    1,
+		},
+	}
+}
+
+// With returns a child Logger that writes the fields with every message.
+// The child shares the configuration of l, so changing one changes both.
+func (l *Logger) With(fields ...Field) *Logger {
+	child := &Logger{core: l.core, name: l.name}
+	child.fields = make([]Field, 0, len(l.fields)+len(fields))
+	child.fields = append(child.fields, l.fields...)
+	child.fields = append(child.fields, fields...)
+	return child
+}
+
+// Default returns the Logger used by the package level functions.
+func Default() *Logger {
+	return std
+}
+
+// SetPrefix sets the prefix shown when ShowPrefix is enabled.
+func (l *Logger) SetPrefix(prefix string) {
+	if l.std {
+		SetPrefix(prefix) // the default Logger reads the package variables
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prefix = prefix
+}
+
+// SetTimeFormat sets the layout used to format the time.
+func (l *Logger) SetTimeFormat(format string) {
+	if l.std {
+		SetTimeFormat(format) // the default Logger reads the package variables
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timeFormat = format
+}
+
+// SetNonColor disables the ANSI colors when set to true.
+func (l *Logger) SetNonColor(nonColor bool) {
+	if l.std {
+		SetNonColor(nonColor) // the default Logger reads the package variables
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nonColor = nonColor
+}
+
+// SetShowDepth enables the caller file, line and function.
+func (l *Logger) SetShowDepth(show bool) {
+	if l.std {
+		SetShowDepth(show) // the default Logger reads the package variables
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.showDepth = show
+}
+
+// SetShowTime enables the time.
+func (l *Logger) SetShowTime(show bool) {
+	if l.std {
+		SetShowTime(show) // the default Logger reads the package variables
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.showTime = show
+}
+
+// SetShowPrefix enables the prefix.
+func (l *Logger) SetShowPrefix(show bool) {
+	if l.std {
+		SetShowPrefix(show) // the default Logger reads the package variables
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.showPrefix = show
+}
+
+// SetCallerDepth sets the depth used when Print is called with depth -1,
+// 2 is the caller of Info and alike. On the default Logger it also applies
+// to the package functions, DefaultCallerDepth is set to depth+1 since they
+// add a frame.
+func (l *Logger) SetCallerDepth(depth int) {
+	if l.std {
+		SetCallerDepth(depth + 1) // the default Logger reads the package variables
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.callerDepth = depth
+}
+
+// SetLevel sets the minimum level to be written, messages below it are
+// dropped before any formatting is done.
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// GetLevel returns the minimum level to be written.
+func (l *Logger) GetLevel() Level {
+	return Level(l.level.Load())
+}
+
+// Enabled reports whether the level would be written, it can be used to
+// guard arguments that are expensive to compute.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.GetLevel()
+}
+
+// SetFormat sets the registered Encoder used to write the lines.
+func (l *Logger) SetFormat(format Format) error {
+	enc, ok := LookupEncoder(format)
+	if !ok {
+		return fmt.Errorf("log: unknown format %q", format)
+	}
+	l.SetEncoder(enc)
+	return nil
+}
+
+// SetEncoder sets the Encoder used to write the lines, nil means
+// TextEncoder.
+func (l *Logger) SetEncoder(enc Encoder) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.encoder = enc
+}
+
+// SetOutput sets the output, nil means stdout. Any io.Writer can be used,
+// a *Writer rotates files, and outputs that can Sync or Close are synced
+// and closed by Sync and FATAL.
+func (l *Logger) SetOutput(w io.Writer) error {
+	if fw, ok := w.(*Writer); ok && fw == nil {
+		w = nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = w
+	return nil
+}
+
+// Sync flushes the output and the handlers that support it, e.g. an async
+// Writer, call it before the program exits.
+func (l *Logger) Sync() error {
+	l.summarize()
+	s := l.snapshot()
+	if s.deduper != nil {
+		l.flushRepeated(s.deduper)
+	}
+
+	var first error
+	if !s.replaced && s.out != nil {
+		if err := syncOutput(s.out); err != nil {
+			first = err
+		}
+	}
+	for _, h := range s.handlers {
+		if err := syncOutput(h); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// SetHandler replaces the output with the handlers, calling it without
+// handlers restores the output.
+func (l *Logger) SetHandler(handlers ...Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers = append([]Handler(nil), handlers...)
+	l.replaced = len(handlers) > 0
+}
+
+// AddHandler adds a handler that receives the records along with the
+// output and the handlers already set.
+func (l *Logger) AddHandler(h Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	handlers := make([]Handler, 0, len(l.handlers)+1)
+	handlers = append(handlers, l.handlers...)
+	l.handlers = append(handlers, h)
+}
+
+func (l *core) snapshot() settings {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	s := settings{
+		prefix:     l.prefix,
+		timeFormat: l.timeFormat,
+		nonColor:   l.nonColor,
+		showDepth:  l.showDepth,
+		showTime:   l.showTime,
+		showPrefix: l.showPrefix,
+		encoder:    l.encoder,
+		out:        l.out,
+		handlers:   l.handlers,
+		replaced:   l.replaced,
+		exitFunc:   l.exitFunc,
+		exitCode:   l.exitCode,
+		fatalHooks: l.fatalHooks,
+		hooks:      l.hooks,
+		hookErrors: l.hookErrors,
+		limiter:    l.limiter,
+		deduper:    l.deduper,
+	}
+	if l.std {
+		configMux.RLock()
+		defer configMux.RUnlock()
+		s.prefix = Prefix
+		s.timeFormat = TimeFormat
+		s.nonColor = NonColor
+		s.showDepth = ShowDepth
+		s.showTime = ShowTime
+		s.showPrefix = ShowPrefix
+	}
+	return s
+}
+
+// dropped reports whether a call is skipped before the caller is known,
+// FATAL and PANIC are never skipped so the program flow doesn't depend on
+// the level. With module levels the caller decides, see caller.
+func (l *Logger) dropped(level Level) bool {
+	return l.modules.Load() == nil && !l.Enabled(level) && level != FATAL && level != PANIC
+}
+
+// caller returns the program counter of the caller depth frames above the
+// caller of caller, and whether the level is written for it.
+func (l *Logger) caller(level Level, depth int) (uintptr, bool) {
+	var pc uintptr
+	var pcs [1]uintptr
+	if runtime.Callers(depth+2, pcs[:]) > 0 {
+		pc = pcs[0]
+	}
+	if level == FATAL || level == PANIC {
+		return pc, true
+	}
+	return pc, level >= l.moduleLevel(pc)
+}
+
+// defaultDepth returns the depth used when Print is called with depth -1.
+func (l *Logger) defaultDepth() int {
+	if l.std {
+		return GetCallerDepth() - 1 // the package functions have one more frame
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.callerDepth
+}
+
+// Print formats the args and writes them with the given level. The depth
+// is the number of stack frames to ascend to find the caller, -1 means the
+// caller depth of the Logger.
+func (l *Logger) Print(level Level, depth int, addNewline bool, args ...any) {
+	if l.dropped(level) {
+		return
+	}
+	if depth == -1 {
+		depth = l.defaultDepth()
+	}
+	pc, ok := l.caller(level, depth)
+	if !ok {
+		return
+	}
+	l.output(nil, level, pc, addNewline, args...)
+}
+
+// PrintContext is like Print but also writes the fields stored in ctx.
+func (l *Logger) PrintContext(ctx context.Context, level Level, depth int, addNewline bool, args ...any) {
+	if l.dropped(level) {
+		return
+	}
+	if depth == -1 {
+		depth = l.defaultDepth()
+	}
+	pc, ok := l.caller(level, depth)
+	if !ok {
+		return
+	}
+	l.output(ctx, level, pc, addNewline, args...)
+}
+
+func (l *Logger) output(ctx context.Context, level Level, pc uintptr, addNewline bool, args ...any) {
+	args, fields := splitFields(args)
+	if len(args) == 0 && len(fields) == 0 {
+		return
+	}
+	if ctxFields := FieldsFromContext(ctx); len(ctxFields) > 0 {
+		fields = append(ctxFields[:len(ctxFields):len(ctxFields)], fields...)
+	}
+	if len(l.fields) > 0 {
+		fields = append(l.fields[:len(l.fields):len(l.fields)], fields...)
+	}
+
+	s := l.snapshot()
+	if !l.allow(s, level, args) {
+		return
+	}
+	if level == FATAL {
+		l.summarize() // the program won't be around for the timer
+	}
+	r := l.record(s, level, pc, addNewline, args, fields)
+	if !l.dedup(s, r) {
+		return
+	}
+	l.dispatch(s, r)
+
+	switch level {
+	case FATAL:
+		l.exit(s, r)
+	case PANIC:
+		panic(r.Message)
+	}
+}
+
+// record builds the Record of one call.
+func (l *Logger) record(s settings, level Level, pc uintptr, addNewline bool, args []any, fields []Field) Record {
+	r := Record{
+		Time:       time.Now(),
+		Level:      level,
+		Prefix:     s.prefix,
+		Message:    sprint(level, addNewline, args, fields),
+		Fields:     fields,
+		TimeFormat: s.timeFormat,
+		NonColor:   s.nonColor,
+		ShowDepth:  s.showDepth,
+		ShowTime:   s.showTime,
+		ShowPrefix: s.showPrefix,
+		PC:         pc,
+	}
+	return r
+}
+
+// dispatch passes the record to the output and the handlers.
+func (l *Logger) dispatch(s settings, r Record) {
+	s.fire(BeforeWrite, r)
+	defer s.fire(AfterWrite, r)
+
+	if !s.replaced {
+		if err := l.write(s, r); err != nil {
+			fmt.Println(err)
+		}
+	}
+	for _, h := range s.handlers {
+		if !h.Enabled(r.Level) {
+			continue
+		}
+		if err := h.Handle(r); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// write encodes the record and writes it to the output with a single Write
+// call, writes are serialized since an io.Writer may not be safe for
+// concurrent use.
+func (l *core) write(s settings, r Record) error {
+	enc := s.encoder
+	if enc == nil {
+		enc = TextEncoder
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := encodeRecord(enc, buf, r); err != nil {
+		return err
+	}
+
+	out := s.out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	l.outMux.Lock()
+	defer l.outMux.Unlock()
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+// Handle passes a record built elsewhere to the output and the handlers,
+// so a Logger can be used as the Handler of another one.
+func (l *Logger) Handle(r Record) error {
+	if len(l.fields) > 0 {
+		r.Fields = append(l.fields[:len(l.fields):len(l.fields)], r.Fields...)
+	}
+	l.dispatch(l.snapshot(), r)
+	return nil
+}
+
+// Debug writes the args with the DEBUG level.
+func (l *Logger) Debug(args ...any) {
+	l.Print(DEBUG, -1, false, args...)
+}
+
+// Info writes the args with the INFO level.
+func (l *Logger) Info(args ...any) {
+	l.Print(INFO, -1, false, args...)
+}
+
+// Warn writes the args with the WARNING level.
+func (l *Logger) Warn(args ...any) {
+	l.Print(WARNING, -1, false, args...)
+}
+
+// Error writes the args with the ERROR level.
+func (l *Logger) Error(args ...any) {
+	l.Print(ERROR, -1, false, args...)
+}
+
+// Fatal writes the args with the FATAL level, runs the fatal hooks, closes
+// the outputs and exits.
+func (l *Logger) Fatal(args ...any) {
+	l.Print(FATAL, -1, false, args...)
+}
+
+// Panic writes the args with the PANIC level and panics with the message.
+func (l *Logger) Panic(args ...any) {
+	l.Print(PANIC, -1, false, args...)
+}
+
+// Message writes the args with the MESSAGE level, C strings are converted.
+func (l *Logger) Message(args ...any) {
+	l.Print(MESSAGE, -1, false, args...)
+}
+
+// Pointer writes the addresses of the args with the POINTER level.
+func (l *Logger) Pointer(args ...any) {
+	l.Print(POINTER, -1, false, args...)
+}
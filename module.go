@@ -0,0 +1,174 @@
+/**---------------------------------------------------------
+ * name: module.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// moduleLevels is an immutable rule table, changing the rules stores a new
+// one so the caches never hold stale entries.
+type moduleLevels struct {
+	levels map[string]Level
+	byPC   sync.Map // uintptr -> moduleMatch
+	byName sync.Map // string -> moduleMatch
+}
+
+type moduleMatch struct {
+	level Level
+	ok    bool
+}
+
+// Named returns a child Logger with the name, dot separated after the name
+// of l, which is matched by the module levels before the package path.
+func (l *Logger) Named(name string) *Logger {
+	child := l.With()
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	child.name = name
+	return child
+}
+
+// SetModuleLevel sets the minimum level of a module, either a package path
+// prefix like "github.com/chunqian/tinylog/test" or a Logger name. The
+// longest matching module wins over the Logger level.
+func (l *Logger) SetModuleLevel(module string, level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	levels := l.moduleLevelsLocked()
+	levels[module] = level
+	l.storeModules(levels)
+}
+
+// RemoveModuleLevel removes the minimum level of a module.
+func (l *Logger) RemoveModuleLevel(module string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	levels := l.moduleLevelsLocked()
+	delete(levels, module)
+	l.storeModules(levels)
+}
+
+// SetModuleLevels replaces all the module levels.
+func (l *Logger) SetModuleLevels(levels map[string]Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	copied := make(map[string]Level, len(levels))
+	for module, level := range levels {
+		copied[module] = level
+	}
+	l.storeModules(copied)
+}
+
+// ModuleLevels returns a copy of the module levels.
+func (l *Logger) ModuleLevels() map[string]Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.moduleLevelsLocked()
+}
+
+func (l *core) moduleLevelsLocked() map[string]Level {
+	levels := make(map[string]Level)
+	if m := l.modules.Load(); m != nil {
+		for module, level := range m.levels {
+			levels[module] = level
+		}
+	}
+	return levels
+}
+
+func (l *core) storeModules(levels map[string]Level) {
+	if len(levels) == 0 {
+		l.modules.Store(nil)
+		return
+	}
+	l.modules.Store(&moduleLevels{levels: levels})
+}
+
+// moduleLevel returns the minimum level for a call of the Logger at pc.
+func (l *Logger) moduleLevel(pc uintptr) Level {
+	m := l.modules.Load()
+	if m == nil {
+		return l.GetLevel()
+	}
+	if l.name != "" {
+		if match := m.matchName(l.name); match.ok {
+			return match.level
+		}
+	}
+	if match := m.matchPC(pc); match.ok {
+		return match.level
+	}
+	return l.GetLevel()
+}
+
+func (m *moduleLevels) matchName(name string) moduleMatch {
+	if v, ok := m.byName.Load(name); ok {
+		return v.(moduleMatch)
+	}
+	match := m.longest(name, '.')
+	m.byName.Store(name, match)
+	return match
+}
+
+func (m *moduleLevels) matchPC(pc uintptr) moduleMatch {
+	if v, ok := m.byPC.Load(pc); ok {
+		return v.(moduleMatch)
+	}
+	var match moduleMatch
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		match = m.longest(packagePath(fn.Name()), '/')
+	}
+	m.byPC.Store(pc, match)
+	return match
+}
+
+// longest returns the level of the longest module equal to s or to a
+// prefix of s ending before sep.
+func (m *moduleLevels) longest(s string, sep byte) moduleMatch {
+	var match moduleMatch
+	best := -1
+	for module, level := range m.levels {
+		if len(module) <= best {
+			continue
+		}
+		if s == module || (strings.HasPrefix(s, module) && s[len(module)] == sep) {
+			match = moduleMatch{level: level, ok: true}
+			best = len(module)
+		}
+	}
+	return match
+}
+
+// packagePath returns the package path of a function name like
+// "github.com/chunqian/tinylog.(*Logger).Info".
+func packagePath(fnName string) string {
+	slash := strings.LastIndexByte(fnName, '/')
+	if dot := strings.IndexByte(fnName[slash+1:], '.'); dot >= 0 {
+		return fnName[:slash+1+dot]
+	}
+	return fnName
+}
+
+// SetModuleLevel sets the minimum level of a module of the default Logger.
+func SetModuleLevel(module string, level Level) {
+	std.SetModuleLevel(module, level)
+}
+
+// RemoveModuleLevel removes the minimum level of a module of the default
+// Logger.
+func RemoveModuleLevel(module string) {
+	std.RemoveModuleLevel(module)
+}
+
+// Named returns a named child of the default Logger.
+func Named(name string) *Logger {
+	return std.Named(name)
+}
@@ -0,0 +1,62 @@
+/**---------------------------------------------------------
+ * name: record.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// A Record holds everything an Encoder needs to write one line. A Logger
+// builds one Record per call and passes it by value to its handlers.
+type Record struct {
+	Time    time.Time
+	Level   Level
+	PC      uintptr // program counter of the caller, 0 if unknown
+	Prefix  string
+	Message string // message with the {} placeholders expanded
+	Fields  []Field
+
+	// layout of the Logger that produced the record
+	TimeFormat string
+	NonColor   bool
+	ShowDepth  bool
+	ShowTime   bool
+	ShowPrefix bool
+}
+
+// Clone returns a copy of the record that shares no state with r.
+func (r Record) Clone() Record {
+	if r.Fields != nil {
+		r.Fields = append([]Field(nil), r.Fields...)
+	}
+	return r
+}
+
+// Frame returns the stack frame of the caller.
+func (r *Record) Frame() runtime.Frame {
+	if r.PC == 0 {
+		return runtime.Frame{}
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	return frame
+}
+
+// caller returns the file, line and function name shown by ShowDepth.
+func (r *Record) caller() (file string, line int, fnName string, ok bool) {
+	frame := r.Frame()
+	if frame.File == "" {
+		return "", 0, "", false
+	}
+	fnName = "?"
+	if frame.Function != "" {
+		fnName = strings.TrimLeft(filepath.Ext(frame.Function), ".")
+	}
+	return filepath.Base(frame.File), frame.Line, fnName, true
+}
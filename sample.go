@@ -0,0 +1,209 @@
+/**---------------------------------------------------------
+ * name: sample.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Sampling keeps the First messages of each level and format string per
+// Interval, then every Thereafter-th one, 0 drops all the others. An
+// Interval <= 0 means one second.
+type Sampling struct {
+	Interval   time.Duration
+	First      int
+	Thereafter int
+}
+
+// defaultSummaryInterval is the sampling interval when none is given, and
+// the delay of the "suppressed" summary line when only a rate limit is set.
+const defaultSummaryInterval = time.Second
+
+type sampleKey struct {
+	level  Level
+	format string
+}
+
+// limiter drops the messages above the sampling and the rate limit and
+// counts them for the summary line.
+type limiter struct {
+	mu sync.Mutex
+
+	sampling    Sampling
+	windowStart time.Time
+	counts      map[sampleKey]int
+
+	rate   float64 // tokens per second, 0 means no rate limit
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	suppressed int
+	summary    *time.Timer
+}
+
+// SetSampling sets the sampling of the messages, nil disables it. FATAL and
+// PANIC are never sampled.
+func (l *Logger) SetSampling(sampling *Sampling) {
+	l.setLimiter(func(lim *limiter) {
+		lim.sampling = Sampling{}
+		if sampling != nil {
+			lim.sampling = *sampling
+		}
+		if lim.sampling.Interval <= 0 {
+			lim.sampling.Interval = defaultSummaryInterval
+		}
+		lim.counts = nil
+	})
+}
+
+// SetRateLimit limits the Logger to rate messages per second with bursts of
+// burst messages, a rate <= 0 disables it. FATAL and PANIC are never
+// limited.
+func (l *Logger) SetRateLimit(rate float64, burst int) {
+	l.setLimiter(func(lim *limiter) {
+		if rate <= 0 {
+			lim.rate = 0
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		lim.rate = rate
+		lim.burst = float64(burst)
+		lim.tokens = lim.burst
+		lim.last = now()
+	})
+}
+
+// setLimiter changes the limiter with fn, the limiter is removed when both
+// the sampling and the rate limit are off so the calls don't lock it.
+func (l *Logger) setLimiter(fn func(lim *limiter)) {
+	l.mu.Lock()
+	lim := l.limiter
+	if lim == nil {
+		lim = &limiter{}
+	}
+	lim.mu.Lock()
+	fn(lim)
+	off := !lim.sampling.enabled() && lim.rate <= 0
+	lim.mu.Unlock()
+	if off {
+		l.limiter = nil
+	} else {
+		l.limiter = lim
+	}
+	l.mu.Unlock()
+
+	if off {
+		l.flushSuppressed(lim)
+	}
+}
+
+func (s Sampling) enabled() bool {
+	return s.First > 0 || s.Thereafter > 0
+}
+
+// allow reports whether the message is written, the first arg is the
+// format string of the sampling key.
+func (l *Logger) allow(s settings, level Level, args []any) bool {
+	lim := s.limiter
+	if lim == nil || level == FATAL || level == PANIC {
+		return true
+	}
+	var format string
+	if len(args) > 0 {
+		format, _ = args[0].(string)
+	}
+
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	t := now()
+	if lim.sampled(sampleKey{level, format}, t) && lim.take(t) {
+		return true
+	}
+	lim.suppressed++
+	if lim.summary == nil {
+		interval := lim.sampling.Interval
+		if interval <= 0 {
+			interval = defaultSummaryInterval
+		}
+		lim.summary = time.AfterFunc(interval, func() { l.flushSuppressed(lim) })
+	}
+	return false
+}
+
+// sampled counts the message in the current window.
+func (lim *limiter) sampled(key sampleKey, t time.Time) bool {
+	if !lim.sampling.enabled() {
+		return true
+	}
+	if lim.counts == nil || t.Sub(lim.windowStart) >= lim.sampling.Interval {
+		lim.counts = make(map[sampleKey]int)
+		lim.windowStart = t
+	}
+	lim.counts[key]++
+	n := lim.counts[key]
+	if n <= lim.sampling.First {
+		return true
+	}
+	return lim.sampling.Thereafter > 0 && (n-lim.sampling.First)%lim.sampling.Thereafter == 0
+}
+
+// take takes a token from the bucket.
+func (lim *limiter) take(t time.Time) bool {
+	if lim.rate <= 0 {
+		return true
+	}
+	lim.tokens += t.Sub(lim.last).Seconds() * lim.rate
+	if lim.tokens > lim.burst {
+		lim.tokens = lim.burst
+	}
+	lim.last = t
+	if lim.tokens < 1 {
+		return false
+	}
+	lim.tokens--
+	return true
+}
+
+// summarize writes the pending summary line of the limiter.
+func (l *Logger) summarize() {
+	if lim := l.snapshot().limiter; lim != nil {
+		l.flushSuppressed(lim)
+	}
+}
+
+// flushSuppressed writes the number of messages suppressed by lim since the
+// last summary as a WARNING line, it bypasses the limiter.
+func (l *Logger) flushSuppressed(lim *limiter) {
+	lim.mu.Lock()
+	n := lim.suppressed
+	lim.suppressed = 0
+	if lim.summary != nil {
+		lim.summary.Stop()
+		lim.summary = nil
+	}
+	lim.mu.Unlock()
+	if n == 0 {
+		return
+	}
+	s := l.snapshot()
+	l.dispatch(s, l.record(s, WARNING, 0, false, []any{fmt.Sprintf("suppressed %d messages", n)}, nil))
+}
+
+// SetSampling sets the sampling of the default Logger.
+func SetSampling(sampling *Sampling) {
+	std.SetSampling(sampling)
+}
+
+// SetRateLimit sets the rate limit of the default Logger.
+func SetRateLimit(rate float64, burst int) {
+	std.SetRateLimit(rate, burst)
+}
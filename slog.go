@@ -0,0 +1,204 @@
+//go:build go1.21
+
+/**---------------------------------------------------------
+ * name: slog.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// slog levels of MESSAGE, POINTER and PANIC, between the standard ones so
+// they map back to the same tinylog level. MESSAGE and POINTER are above
+// INFO since tinylog never filters them.
+const (
+	slogLevelMessage = slog.LevelInfo + 1
+	slogLevelPointer = slog.LevelInfo + 2
+	slogLevelPanic   = slog.LevelError + 2
+	slogLevelFatal   = slog.LevelError + 4
+)
+
+// SlogLevel returns the slog level of a tinylog level.
+func SlogLevel(level Level) slog.Level {
+	switch level {
+	case DEBUG:
+		return slog.LevelDebug
+	case INFO:
+		return slog.LevelInfo
+	case WARNING:
+		return slog.LevelWarn
+	case ERROR:
+		return slog.LevelError
+	case FATAL:
+		return slogLevelFatal
+	case MESSAGE:
+		return slogLevelMessage
+	case POINTER:
+		return slogLevelPointer
+	case PANIC:
+		return slogLevelPanic
+	}
+	return slog.LevelInfo
+}
+
+// LevelFromSlog returns the tinylog level of a slog level, levels in
+// between the standard ones are rounded down.
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level == slogLevelMessage:
+		return MESSAGE
+	case level == slogLevelPointer:
+		return POINTER
+	case level == slogLevelPanic:
+		return PANIC
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARNING
+	case level < slogLevelFatal:
+		return ERROR
+	}
+	return FATAL
+}
+
+// SlogReplaceAttr can be used as slog.HandlerOptions.ReplaceAttr to write
+// the tinylog level names instead of "INFO+1" and alike.
+func SlogReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.LevelKey {
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(LevelFromSlog(level).String())
+		}
+	}
+	return a
+}
+
+// A SlogHandler is a slog.Handler that writes the slog records through a
+// tinylog Logger, so they get its text layout, encoders and handlers.
+type SlogHandler struct {
+	l      *Logger
+	prefix string // group prefix of the attr keys
+}
+
+var _ slog.Handler = (*SlogHandler)(nil) // check if object implements interface
+
+// NewSlogHandler returns a slog.Handler writing to l, nil means the
+// default Logger.
+func NewSlogHandler(l *Logger) *SlogHandler {
+	if l == nil {
+		l = std
+	}
+	return &SlogHandler{l: l}
+}
+
+// Enabled reports whether the Logger writes the level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.Enabled(LevelFromSlog(level))
+}
+
+// Handle converts the slog record and passes it to the Logger with the
+// ctx fields. FATAL and PANIC records are written but don't exit or panic.
+func (h *SlogHandler) Handle(ctx context.Context, sr slog.Record) error {
+	s := h.l.snapshot()
+	r := Record{
+		Time:       sr.Time,
+		Level:      LevelFromSlog(sr.Level),
+		PC:         sr.PC,
+		Prefix:     s.prefix,
+		Message:    sr.Message,
+		TimeFormat: s.timeFormat,
+		NonColor:   s.nonColor,
+		ShowDepth:  s.showDepth,
+		ShowTime:   s.showTime,
+		ShowPrefix: s.showPrefix,
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	ctxFields := FieldsFromContext(ctx)
+	if n := sr.NumAttrs(); n > 0 || len(ctxFields) > 0 {
+		r.Fields = make([]Field, 0, len(ctxFields)+n)
+		r.Fields = append(r.Fields, ctxFields...)
+		sr.Attrs(func(a slog.Attr) bool {
+			r.Fields = appendSlogAttr(r.Fields, h.prefix, a)
+			return true
+		})
+	}
+	return h.l.Handle(r)
+}
+
+// WithAttrs returns a handler whose Logger writes the attrs as fields.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, h.prefix, a)
+	}
+	return &SlogHandler{l: h.l.With(fields...), prefix: h.prefix}
+}
+
+// WithGroup returns a handler that qualifies the following attr keys with
+// the group name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{l: h.l, prefix: h.prefix + name + "."}
+}
+
+// appendSlogAttr flattens groups into dotted keys, slog.Any values are
+// kept as is so they are rendered with pretty.Formatter.
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSlogAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
+
+// A slogBackend is a Handler that forwards the records to a slog.Handler.
+type slogBackend struct {
+	h slog.Handler
+}
+
+// NewSlogBackend returns a Handler that forwards the records to h, set it
+// with SetHandler to back a Logger by any slog.Handler.
+func NewSlogBackend(h slog.Handler) Handler {
+	return slogBackend{h: h}
+}
+
+// Enabled asks the slog.Handler.
+func (b slogBackend) Enabled(level Level) bool {
+	return b.h.Enabled(context.Background(), SlogLevel(level))
+}
+
+// Handle converts the record and passes it to the slog.Handler.
+func (b slogBackend) Handle(r Record) error {
+	sr := slog.NewRecord(r.Time, SlogLevel(r.Level), r.Message, r.PC)
+	for _, f := range r.Fields {
+		sr.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	return b.h.Handle(context.Background(), sr)
+}
+
+// NewFromSlog returns a Logger backed by the slog.Handler.
+func NewFromSlog(h slog.Handler) *Logger {
+	l := New()
+	l.SetHandler(NewSlogBackend(h))
+	return l
+}
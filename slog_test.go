@@ -0,0 +1,71 @@
+//go:build go1.21
+
+/**---------------------------------------------------------
+ * name: slog_test.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogLevel(t *testing.T) {
+	for level := DEBUG; level <= PANIC; level++ {
+		if got := LevelFromSlog(SlogLevel(level)); got != level {
+			t.Errorf("%s round-trips to %s", level, got)
+		}
+	}
+	for _, level := range []Level{MESSAGE, POINTER} {
+		if SlogLevel(level) < slog.LevelInfo {
+			t.Errorf("%s is below slog.LevelInfo", level)
+		}
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.SetNonColor(true)
+	l.SetOutput(&buf)
+	l.SetLevel(INFO)
+
+	sl := slog.New(NewSlogHandler(l)).With("app", "tiny").WithGroup("req")
+	ctx := ContextWithFields(context.Background(), String("trace", "t1"))
+	sl.InfoContext(ctx, "hello", "id", 1, slog.Group("user", "name", "bob"), slog.Group("", "flat", true))
+	sl.Debug("dropped")
+
+	want := "[INFO] hello app=tiny trace=t1 req.id=1 req.user.name=bob req.flat=true\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNewFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewFromSlog(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: SlogReplaceAttr}))
+	l.Pointer("p")
+	l.Info("Say: {}", "Hello", Int("id", 1))
+
+	dec := json.NewDecoder(&buf)
+	for _, want := range []map[string]any{
+		{"level": "POINTER"},
+		{"level": "INFO", "msg": "Say: Hello", "id": float64(1)},
+	} {
+		var got map[string]any
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("%s: got %v, want %v", k, got[k], v)
+			}
+		}
+	}
+}
@@ -0,0 +1,273 @@
+/**---------------------------------------------------------
+ * name: template.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/chunqian/tinylog/pretty"
+)
+
+// maxTemplates bounds the template cache, formats built at runtime would
+// grow it forever. Templates past the bound are parsed on every call.
+const maxTemplates = 4096
+
+// notFound replaces the args missing for a {} placeholder.
+const notFound = "not found!"
+
+var (
+	templates     sync.Map // format string -> *template
+	templatesSize atomic.Int32
+)
+
+// Placeholder kinds.
+const (
+	nextArg  = iota // {}
+	indexArg        // {0}
+	namedArg        // {name}
+)
+
+// A segment is a literal text followed by an optional placeholder.
+type segment struct {
+	text  string
+	kind  int
+	index int
+	name  string
+	raw   string // the placeholder as written, kept when it can't be resolved
+	hole  bool   // if false, the segment is only text
+}
+
+// A template is a parsed format string.
+type template struct {
+	segments []segment
+	holes    bool // if false, the format has no placeholder
+	next     bool // if true, the format has a {} placeholder
+}
+
+// parseTemplate returns the cached template of the format.
+func parseTemplate(format string) *template {
+	if t, ok := templates.Load(format); ok {
+		return t.(*template)
+	}
+	t := newTemplate(format)
+	if templatesSize.Load() < maxTemplates {
+		if _, loaded := templates.LoadOrStore(format, t); !loaded {
+			templatesSize.Add(1)
+		}
+	}
+	return t
+}
+
+// newTemplate splits the format at the {}, {0} and {name} placeholders,
+// {{ and }} are written as { and } when the format has a placeholder. Other
+// braces are kept as they are.
+func newTemplate(format string) *template {
+	t := &template{}
+	var text strings.Builder
+	start := 0
+	for i := 0; i < len(format); i++ {
+		switch format[i] {
+		case '{':
+			if i+1 < len(format) && format[i+1] == '{' {
+				text.WriteString(format[start : i+1])
+				i++
+				start = i + 1
+				continue
+			}
+			end := strings.IndexByte(format[i+1:], '}')
+			if end < 0 {
+				continue
+			}
+			seg, ok := parseHole(format[i+1 : i+1+end])
+			if !ok {
+				continue
+			}
+			text.WriteString(format[start:i])
+			seg.text = text.String()
+			seg.raw = format[i : i+end+2]
+			t.next = t.next || seg.kind == nextArg
+			text.Reset()
+			t.segments = append(t.segments, seg)
+			t.holes = true
+			i += end + 1
+			start = i + 1
+		case '}':
+			if i+1 < len(format) && format[i+1] == '}' {
+				text.WriteString(format[start : i+1])
+				i++
+				start = i + 1
+			}
+		}
+	}
+	text.WriteString(format[start:])
+	if text.Len() > 0 {
+		t.segments = append(t.segments, segment{text: text.String()})
+	}
+	return t
+}
+
+// parseHole parses the inside of a placeholder.
+func parseHole(s string) (segment, bool) {
+	if s == "" {
+		return segment{kind: nextArg, hole: true}, true
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && s[0] != '+' {
+		return segment{kind: indexArg, index: n, hole: true}, true
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && (c == '.' || c >= '0' && c <= '9') {
+			continue
+		}
+		return segment{}, false
+	}
+	return segment{kind: namedArg, name: s, hole: true}, true
+}
+
+// resolves reports whether a placeholder of the template takes a value, a
+// format like "got {id}" with unrelated args is not a template.
+func (t *template) resolves(args []any, fields []Field) bool {
+	if t.next {
+		return true
+	}
+	for _, seg := range t.segments {
+		switch {
+		case seg.hole && seg.kind == indexArg && seg.index < len(args):
+			return true
+		case seg.hole && seg.kind == namedArg:
+			for _, f := range fields {
+				if f.Key == seg.name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// execute writes the template with the args, named placeholders are looked
+// up in the fields. Missing {} args are written as "not found!", {0} and
+// {name} that can't be resolved are written as they are. The args no
+// placeholder used are appended after ", ".
+func (t *template) execute(buf *bytes.Buffer, level Level, args []any, fields []Field) {
+	var usedBuf [16]bool
+	used := usedBuf[:]
+	if len(args) > len(usedBuf) {
+		used = make([]bool, len(args))
+	}
+
+	next := 0
+	for _, seg := range t.segments {
+		buf.WriteString(seg.text)
+		if !seg.hole {
+			continue
+		}
+
+		switch seg.kind {
+		case nextArg:
+			if next < len(args) {
+				appendArg(buf, level, args[next])
+				used[next] = true
+			} else {
+				buf.WriteString(notFound)
+			}
+			next++
+		case indexArg:
+			if seg.index < len(args) {
+				appendArg(buf, level, args[seg.index])
+				used[seg.index] = true
+			} else {
+				buf.WriteString(seg.raw)
+			}
+		case namedArg:
+			if value, ok := lookupField(fields, seg.name); ok {
+				buf.WriteString(value)
+			} else {
+				buf.WriteString(seg.raw)
+			}
+		}
+	}
+
+	for i, arg := range args {
+		if !used[i] {
+			buf.WriteString(", ")
+			appendArg(buf, level, arg)
+		}
+	}
+}
+
+// lookupField returns the text of the last field with the key.
+func lookupField(fields []Field, key string) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == key {
+			return formatValue(fields[i].Value), true
+		}
+	}
+	return "", false
+}
+
+// appendArg writes an arg, C strings are converted for MESSAGE and the
+// address is written for POINTER. Primitives are appended directly.
+func appendArg(buf *bytes.Buffer, level Level, value any) {
+	if level == MESSAGE {
+		if v, ok := value.(*int8); ok {
+			value = pretty.Gostring(v)
+		}
+		if v, ok := value.(*uint8); ok {
+			value = pretty.Gostring(v)
+		}
+		if v, ok := value.(unsafe.Pointer); ok {
+			value = pretty.Gostring2(v)
+		}
+	}
+	if level == POINTER {
+		value = fmt.Sprintf("%p", value)
+	}
+	if v, ok := value.(string); ok {
+		buf.WriteString(strings.ReplaceAll(v, "interface {}", "any"))
+		return
+	}
+	if appendPrimitive(buf, value) {
+		return
+	}
+	buf.WriteString(strings.ReplaceAll(fmt.Sprintf("%# v", pretty.Formatter(value)), "interface {}", "any"))
+}
+
+// sprint expands the placeholders of the format string in args[0] into the
+// message text. Without a format string the args are joined with ", ".
+func sprint(level Level, addNewline bool, args []any, fields []Field) string {
+	if len(args) == 0 {
+		return ""
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	var t *template
+	if format, ok := args[0].(string); ok {
+		t = parseTemplate(format)
+	}
+	if t != nil && t.holes && t.resolves(args[1:], fields) {
+		t.execute(buf, level, args[1:], fields)
+	} else {
+		for i, arg := range args {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			appendArg(buf, level, arg)
+		}
+	}
+	if addNewline {
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
@@ -0,0 +1,181 @@
+/**---------------------------------------------------------
+ * name: template_test.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/chunqian/tinylog/pretty"
+)
+
+func TestSprint(t *testing.T) {
+	for _, tt := range []struct {
+		args   []any
+		fields []Field
+		want   string
+	}{
+		{[]any{"plain"}, nil, "plain"},
+		{[]any{"a", "b"}, nil, "a, b"},
+		{[]any{1, true}, nil, "int(1), bool(true)"},
+		{[]any{"{} and {}", "x", "y"}, nil, "x and y"},
+		{[]any{"{} and {}", "x"}, nil, "x and not found!"},
+		{[]any{"{}", "x", "y"}, nil, "x, y"},
+		{[]any{"{1} before {0}", "x", "y"}, nil, "y before x"},
+		{[]any{"{0} {} {}", "x", "y"}, nil, "x x y"},
+		{[]any{"{5}", "x"}, nil, "{5}, x"},
+		{[]any{"{1}", "x", "y"}, nil, "y, x"},
+		{[]any{"cfg {path}", "x"}, nil, "cfg {path}, x"},
+		{[]any{"got {id}", 5}, nil, "got {id}, int(5)"},
+		{[]any{"{id} {}", "x"}, nil, "{id} x"},
+		{[]any{"retry {0}"}, nil, "retry {0}"},
+		{[]any{"{{}} is {}", "x"}, nil, "{} is x"},
+		{[]any{"{{literal}}"}, nil, "{{literal}}"},
+		{[]any{"use {{ here", "x"}, nil, "use {{ here, x"},
+		{[]any{"user {user} id {}", 7}, []Field{String("user", "tiny")}, "user tiny id int(7)"},
+		{[]any{"config {path} loaded"}, nil, "config {path} loaded"},
+		{[]any{`json {"a": 1} {}`, "x"}, nil, `json {"a": 1} x`},
+		{[]any{"open { and {}", "x"}, nil, "open { and x"},
+	} {
+		if got := sprint(INFO, false, tt.args, tt.fields); got != tt.want {
+			t.Errorf("sprint(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	format := "cached {}"
+	first := parseTemplate(format)
+	if parseTemplate(format) != first {
+		t.Error("template not cached")
+	}
+}
+
+func BenchmarkSprint(b *testing.B) {
+	args := []any{"request {} took {} ms, status {}", "GET /", "12", "200"}
+	b.Run("template", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sprint(INFO, false, args, nil)
+		}
+	})
+	b.Run("regexp", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			regexpSprint(INFO, false, args...)
+		}
+	})
+}
+
+// regexpSprint is the former implementation, kept as the benchmark
+// baseline.
+func regexpSprint(level Level, addNewline bool, args ...any) string {
+	var buf bytes.Buffer
+
+	top := len(args)
+	if top == 0 {
+		return ""
+	}
+
+	switch args[0].(type) {
+	case string:
+		matched, _ := regexp.MatchString(`{}`, args[0].(string))
+		if !matched {
+			args = regexpExpandToFront(args...)
+		} else {
+			args = regexpExpandToEnd(args...)
+		}
+		top = len(args)
+	default:
+		args = regexpExpandToFront(args...)
+		top = len(args)
+	}
+
+	var format string
+	var formatSlice []string
+
+	// Add all the string arguments to the buffer
+	for i := 0; i < top; i++ {
+		var value = args[i]
+		if i == 0 {
+			format = value.(string)
+			formatSlice = strings.Split(format, "{}")
+		}
+		if i >= len(formatSlice) {
+			break
+		}
+
+		if i > 0 {
+			var mStr = ""
+			if level == MESSAGE {
+				if v, ok := value.(*int8); ok {
+					value = pretty.Gostring(v)
+				}
+				if v, ok := value.(*uint8); ok {
+					value = pretty.Gostring(v)
+				}
+				if v, ok := value.(unsafe.Pointer); ok {
+					value = pretty.Gostring2(v)
+				}
+			}
+			if level == POINTER {
+				value = fmt.Sprintf("%p", value)
+			}
+			switch value.(type) {
+			case string:
+				mStr = strings.ReplaceAll(value.(string), "interface {}", "any")
+			default:
+				mStr = strings.ReplaceAll(fmt.Sprintf("%# v", pretty.Formatter(value)), "interface {}", "any")
+			}
+			buf.WriteString(mStr)
+		}
+		buf.WriteString(formatSlice[i])
+	}
+	if addNewline {
+		buf.WriteString("\n")
+	}
+
+	return buf.String()
+}
+
+func regexpExpandToFront(args ...any) []any {
+	top := len(args)
+	args = append(args, 0)
+	copy(args[1:], args[:])
+	var fmtStr = ""
+	for i := 0; i < top; i++ {
+		if i == top-1 {
+			fmtStr += "{}"
+		} else {
+			fmtStr += "{}, "
+		}
+	}
+	args[0] = fmtStr
+	return args
+}
+
+func regexpExpandToEnd(args ...any) []any {
+	var count = 0
+	switch args[0].(type) {
+	case string:
+		s := strings.Split(args[0].(string), "{}")
+		count = len(s) - 1
+	}
+
+	var diff_count = 0
+	if len(args)-1 < count {
+		diff_count = count - (len(args) - 1)
+	}
+	for i := 0; i < diff_count; i++ {
+		args = append(args, "not found!")
+	}
+	return args
+}
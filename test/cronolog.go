@@ -10,6 +10,7 @@ package main
 
 import (
 	"os"
+	"time"
 
 	log "github.com/chunqian/tinylog"
 )
@@ -17,6 +18,9 @@ import (
 func init() {
 	// create writer to writes message to a set of output files
 	getwd, _ := os.Getwd()
-	writer, _ := log.NewWriter(getwd + "/logs/%Y/%m/%d/test.log")
+	writer, _ := log.NewWriter(getwd+"/logs/%Y/%m/%d/test.log",
+		log.WithSymlink(getwd+"/logs/current.log"), // stable link to the current file
+		log.WithLocation(time.UTC),                 // rotate on UTC dates
+	)
 	log.SetOutput(writer)
 }
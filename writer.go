@@ -8,11 +8,17 @@
 package log
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/lestrrat-go/strftime"
@@ -21,14 +27,46 @@ import (
 type (
 	// A Writer writes message to a set of output files.
 	Writer struct {
-		pattern *strftime.Strftime // given pattern
-		path    string             // current file path
-		symlink *strftime.Strftime // symbolic link to current file path
-		fp      *os.File           // current file pointer
-		loc     *time.Location
-		mux     sync.Locker
-		init    bool // if true, open the file when New() method is called
+		pattern  *strftime.Strftime // given pattern
+		path     string             // current file path
+		symlink  *strftime.Strftime // symbolic link to current file path
+		fp       *os.File           // current file pointer
+		loc      *time.Location
+		mux      sync.Locker
+		init     bool          // if true, open the file when New() method is called
+		maxSize  int64         // if > 0, roll the file to a numbered sibling when it would exceed it
+		size     int64         // current file size
+		maxAge   time.Duration // if > 0, remove files older than it after a rotation
+		maxFile  int           // if > 0, keep only this many newest files after a rotation
+		glob     string        // matches the files created from the pattern
+		root     string        // static directory of the pattern, never pruned
+		compress bool          // if true, gzip the previous file after a rotation
+		wg       sync.WaitGroup
+		err      error // first error of the options
+
+		// retention runs from the compress goroutines, it is serialized by
+		// retainMux which also guards the fields below
+		retainMux   sync.Mutex
+		current     string          // current file path, kept by retention
+		compressing map[string]bool // files being compressed, kept by retention
+
+		// async buffered mode, see WithAsync
+		queue    chan []byte
+		overflow Overflow
+		flushReq chan chan struct{}
+		quit     chan struct{}
+		stopped  chan struct{}
+		closed   atomic.Bool
+		dropped  atomic.Uint64
+		buf      []byte        // pending bytes of the current file
+		bufSize  int           // if > 0, flush the pending bytes beyond it
+		interval time.Duration // flush the pending bytes at this interval
 	}
+
+	// An Option configures a Writer.
+	Option func(*Writer)
+
+	nopMutex struct{}
 )
 
 var (
@@ -36,8 +74,78 @@ var (
 	now                = time.Now       // for test
 )
 
+// WithSymlink keeps a symbolic link, formatted from the strftime pattern,
+// pointing to the current file, e.g. logs/current.log.
+func WithSymlink(pattern string) Option {
+	return func(c *Writer) {
+		p, err := strftime.New(pattern)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.symlink = p
+	}
+}
+
+// WithLocation sets the location used to format the pattern, e.g. time.UTC
+// for UTC based rotation. The default is time.Local.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Writer) {
+		c.loc = loc
+	}
+}
+
+// WithoutLock disables the mutex, use it only when the writes are already
+// serialized by the caller. It can't be combined with WithAsync.
+func WithoutLock() Option {
+	return func(c *Writer) {
+		c.mux = nopMutex{}
+	}
+}
+
+// WithInit opens the file when NewWriter is called instead of on the first
+// write, so a bad pattern or permission shows up early.
+func WithInit() Option {
+	return func(c *Writer) {
+		c.init = true
+	}
+}
+
+// WithMaxSize rolls the current file to a numbered sibling (e.g. test.log.1)
+// when a write would make it exceed size bytes. It is combinable with the
+// time based rotation of the pattern.
+func WithMaxSize(size int64) Option {
+	return func(c *Writer) {
+		c.maxSize = size
+	}
+}
+
+// WithMaxAge removes the files matching the pattern that were last
+// modified more than age ago, checked after each rotation.
+func WithMaxAge(age time.Duration) Option {
+	return func(c *Writer) {
+		c.maxAge = age
+	}
+}
+
+// WithMaxFiles keeps only the n newest files matching the pattern,
+// including the numbered siblings, checked after each rotation.
+func WithMaxFiles(n int) Option {
+	return func(c *Writer) {
+		c.maxFile = n
+	}
+}
+
+// WithCompress gzips the previous file in the background after a rotation,
+// the compressed file replaces the original.
+func WithCompress() Option {
+	return func(c *Writer) {
+		c.compress = true
+	}
+}
+
 // New returns a Writer with the given pattern.
-func NewWriter(pattern string) (*Writer, error) {
+func NewWriter(pattern string, options ...Option) (*Writer, error) {
 	p, err := strftime.New(pattern)
 	if err != nil {
 		return nil, err
@@ -53,44 +161,85 @@ func NewWriter(pattern string) (*Writer, error) {
 		init:    false,
 	}
 
+	for _, option := range options {
+		option(c)
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if _, ok := c.mux.(nopMutex); ok && c.queue != nil {
+		// the flusher goroutine writes concurrently with the caller
+		return nil, errors.New("log: WithoutLock can't be used with WithAsync")
+	}
+	c.glob, c.root = patternGlob(pattern)
+
 	if c.init {
-		if _, err := c.Write([]byte("")); err != nil {
+		if _, err := c.writeLocked([]byte("")); err != nil {
 			return nil, err
 		}
 	}
+	if c.queue != nil {
+		c.start()
+	} else {
+		c.bufSize = 0 // only an async Writer has a flusher
+	}
 	return c, nil
 }
 
 // Write writes to the file and rotate files automatically based on current date and time.
+// In async mode the bytes are queued and written in the background.
 func (c *Writer) Write(b []byte) (int, error) {
+	if c.queue != nil {
+		return c.enqueue(b)
+	}
+
 	c.mux.Lock()
 	defer c.mux.Unlock()
+	return c.writeLocked(b)
+}
 
+func (c *Writer) writeLocked(b []byte) (int, error) {
 	t := now().In(c.loc)
 	path := c.pattern.FormatString(t)
 
 	if c.path != path {
+		if err := c.flushLocked(); err != nil {
+			fmt.Println(err)
+		}
+
 		// close file
-		go func(fp *os.File) {
-			if fp == nil {
+		prev := c.fp
+		prevPath := c.path
+		c.fp = nil
+		c.path = ""
+		if prev != nil && c.compress {
+			c.markCompressing(prevPath)
+		}
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			if prev == nil {
 				return
 			}
-			fp.Close()
-		}(c.fp)
+			prev.Close()
+			if c.compress {
+				c.compressAndRetain(prevPath)
+			}
+		}()
 
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		if err := c.open(t, path); err != nil {
 			return c.write(nil, err)
 		}
-
-		fp, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
-		if err != nil {
+		if prev == nil || !c.compress {
+			c.removeExpired()
+		}
+	} else if c.maxSize > 0 && c.size > 0 && c.size+int64(len(b)) > c.maxSize {
+		if err := c.roll(t); err != nil {
 			return c.write(nil, err)
 		}
-		c.createSymlink(t, path)
-
-		c.path = path
-		c.fp = fp
+		if !c.compress {
+			c.removeExpired()
+		}
 	}
 
 	return c.write(b, nil)
@@ -103,6 +252,256 @@ func (c *Writer) Path() string {
 	return c.path
 }
 
+// open opens path for appending and makes it the current file.
+func (c *Writer) open(t time.Time, path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	fp, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	c.createSymlink(t, path)
+
+	var size int64
+	if fi, err := fp.Stat(); err == nil {
+		size = fi.Size()
+	}
+
+	c.path = path
+	c.fp = fp
+	c.size = size
+
+	c.retainMux.Lock()
+	c.current = path
+	c.retainMux.Unlock()
+	return nil
+}
+
+// roll renames the current file to the next free numbered sibling and
+// opens a fresh one.
+func (c *Writer) roll(t time.Time) error {
+	path := c.path
+	if err := c.flushLocked(); err != nil {
+		fmt.Println(err)
+	}
+	if err := c.fp.Close(); err != nil {
+		fmt.Println(err)
+	}
+	c.fp = nil
+	c.path = ""
+
+	sibling := nextSibling(path)
+	if err := os.Rename(path, sibling); err != nil {
+		return err
+	}
+	if c.compress {
+		c.markCompressing(sibling)
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.compressAndRetain(sibling)
+		}()
+	}
+	return c.open(t, path)
+}
+
+// markCompressing hides path and its partial .gz from the retention until
+// compressAndRetain is done with it.
+func (c *Writer) markCompressing(path string) {
+	c.retainMux.Lock()
+	defer c.retainMux.Unlock()
+	if c.compressing == nil {
+		c.compressing = make(map[string]bool)
+	}
+	c.compressing[path] = true
+}
+
+// compressAndRetain compresses path, then applies the retention, so the
+// scan sees the finished .gz instead of the file being compressed.
+func (c *Writer) compressAndRetain(path string) {
+	compressFile(path)
+
+	c.retainMux.Lock()
+	delete(c.compressing, path)
+	c.retainMux.Unlock()
+	c.removeExpired()
+}
+
+// nextSibling returns the first path.N that doesn't exist, compressed or not.
+func nextSibling(path string) string {
+	for n := 1; ; n++ {
+		sibling := path + "." + strconv.Itoa(n)
+		if exists(sibling) || exists(sibling+".gz") {
+			continue
+		}
+		return sibling
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return !os.IsNotExist(err)
+}
+
+// compressFile gzips path to path.gz and removes path.
+func compressFile(path string) {
+	if err := gzipFile(path); err != nil {
+		fmt.Println(err)
+		os.Remove(path + ".gz")
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode())
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	zw.ModTime = fi.ModTime()
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	// keep the modification time for the retention policy
+	return os.Chtimes(path+".gz", fi.ModTime(), fi.ModTime())
+}
+
+// patternGlob converts the strftime pattern to a glob matching the files
+// it creates, and returns the directory before the first conversion.
+func patternGlob(pattern string) (glob, root string) {
+	var buf strings.Builder
+	verb := -1 // index of the first conversion in pattern
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		if ch == '%' && i+1 < len(pattern) {
+			i++
+			switch pattern[i] {
+			case '%':
+				buf.WriteByte('%')
+			case 'n':
+				buf.WriteByte('\n')
+			case 't':
+				buf.WriteByte('\t')
+			default:
+				if verb < 0 {
+					verb = i - 1
+				}
+				buf.WriteByte('*')
+			}
+			continue
+		}
+		switch ch {
+		case '*', '?', '[', '\\':
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(ch)
+	}
+	glob = buf.String()
+	if verb < 0 {
+		return glob, filepath.Dir(pattern)
+	}
+	return glob, filepath.Dir(pattern[:verb] + "x")
+}
+
+// removeExpired removes the files beyond the retention limits and prunes
+// the directories they leave empty. The current file is always kept.
+func (c *Writer) removeExpired() {
+	if c.maxAge <= 0 && c.maxFile <= 0 {
+		return
+	}
+
+	c.retainMux.Lock()
+	defer c.retainMux.Unlock()
+
+	var matches []string
+	for _, glob := range []string{c.glob, c.glob + ".*"} {
+		m, err := filepath.Glob(glob)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		matches = append(matches, m...)
+	}
+
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+	var files []file
+	seen := make(map[string]bool, len(matches))
+	for _, path := range matches {
+		// a pattern ending with a conversion matches the siblings twice
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if path == c.current || c.compressing[path] || c.compressing[strings.TrimSuffix(path, ".gz")] {
+			continue
+		}
+		fi, err := os.Lstat(path)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, file{path, fi.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	cutoff := now().Add(-c.maxAge)
+	for i, f := range files {
+		// the current file counts as the newest one
+		expired := c.maxFile > 0 && i+1 >= c.maxFile
+		if c.maxAge > 0 && f.modTime.Before(cutoff) {
+			expired = true
+		}
+		if !expired {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		c.pruneDir(filepath.Dir(f.path))
+	}
+}
+
+// pruneDir removes dir and its parents while they are empty, up to the
+// static directory of the pattern.
+func (c *Writer) pruneDir(dir string) {
+	for dir != c.root && strings.HasPrefix(dir, c.root) {
+		if err := os.Remove(dir); err != nil {
+			return // not empty
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
 func (c *Writer) createSymlink(t time.Time, path string) {
 	if c.symlink == nil {
 		return
@@ -114,7 +513,12 @@ func (c *Writer) createSymlink(t time.Time, path string) {
 		return // ignore error
 	}
 
-	if _, err := os.Stat(symlink); err == nil {
+	if err := os.MkdirAll(filepath.Dir(symlink), os.ModePerm); err != nil {
+		fmt.Println(err)
+		return // ignore error
+	}
+
+	if _, err := os.Lstat(symlink); err == nil {
 		if err := os.Remove(symlink); err != nil {
 			fmt.Println(err)
 			return // ignore error
@@ -127,12 +531,29 @@ func (c *Writer) createSymlink(t time.Time, path string) {
 	}
 }
 
-// Close closes file.
+func (nopMutex) Lock()   {}
+func (nopMutex) Unlock() {}
+
+// Close flushes the pending bytes, closes file and waits for the background
+// compressions.
 func (c *Writer) Close() error {
+	if c.queue != nil {
+		if !c.closed.CompareAndSwap(false, true) {
+			return os.ErrClosed
+		}
+		close(c.quit)
+		<-c.stopped
+	}
+
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
-	return c.fp.Close()
+	if err := c.flushLocked(); err != nil {
+		fmt.Println(err)
+	}
+	err := c.fp.Close()
+	c.wg.Wait()
+	return err
 }
 
 func (c *Writer) write(b []byte, err error) (int, error) {
@@ -141,5 +562,28 @@ func (c *Writer) write(b []byte, err error) (int, error) {
 		return 0, err
 	}
 
-	return c.fp.Write(b)
+	if c.bufSize > 0 {
+		c.buf = append(c.buf, b...)
+		c.size += int64(len(b))
+		if len(c.buf) >= c.bufSize {
+			if err := c.flushLocked(); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+
+	n, err := c.fp.Write(b)
+	c.size += int64(n)
+	return n, err
+}
+
+// flushLocked writes the pending bytes to the current file.
+func (c *Writer) flushLocked() error {
+	if len(c.buf) == 0 {
+		return nil
+	}
+	_, err := c.fp.Write(c.buf)
+	c.buf = c.buf[:0]
+	return err
 }
@@ -0,0 +1,301 @@
+/**---------------------------------------------------------
+ * name: writer_test.go
+ * author: shenchunqian
+ * created: 2026-10-18
+ ---------------------------------------------------------*/
+
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setNow(t *testing.T, tm time.Time) {
+	t.Helper()
+	old := now
+	now = func() time.Time { return tm }
+	t.Cleanup(func() { now = old })
+}
+
+func TestWriterMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	setNow(t, time.Date(2022, 8, 15, 10, 0, 0, 0, time.Local))
+
+	w, err := NewWriter(dir+"/%Y%m%d/test.log", WithMaxSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for _, s := range []string{"12345\n", "12345\n", "12345\n"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base := filepath.Join(dir, "20220815", "test.log")
+	for _, path := range []string{base, base + ".1", base + ".2"} {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "12345\n" {
+			t.Errorf("%s: got %q", path, b)
+		}
+	}
+
+	// the time based rotation starts over with a fresh file
+	setNow(t, time.Date(2022, 8, 16, 10, 0, 0, 0, time.Local))
+	if _, err := w.Write([]byte("next\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Path(); got != filepath.Join(dir, "20220816", "test.log") {
+		t.Errorf("path %s", got)
+	}
+}
+
+func TestWriterRetention(t *testing.T) {
+	dir := t.TempDir()
+	day := func(d int) time.Time { return time.Date(2022, 8, d, 10, 0, 0, 0, time.Local) }
+
+	w, err := NewWriter(dir+"/%Y/%m/%d/test.log", WithMaxFiles(2), WithMaxAge(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for d := 10; d <= 13; d++ {
+		setNow(t, day(d))
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatal(err)
+		}
+		path := w.Path()
+		if err := os.Chtimes(path, day(d), day(d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for d, kept := range map[int]bool{10: false, 11: false, 12: true, 13: true} {
+		path := filepath.Join(dir, "2022", "08", strconv.Itoa(d), "test.log")
+		if _, err := os.Stat(path); (err == nil) != kept {
+			t.Errorf("%s: kept %v, want %v", path, err == nil, kept)
+		}
+	}
+	// emptied date directories are pruned, the static root is kept
+	if _, err := os.Stat(filepath.Join(dir, "2022", "08", "10")); !os.IsNotExist(err) {
+		t.Errorf("directory not pruned: %v", err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Error(err)
+	}
+
+	// files older than the max age go even when the count allows them
+	w2, err := NewWriter(dir+"/%Y/%m/%d/test.log", WithMaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w2.Close()
+	setNow(t, day(20))
+	if _, err := w2.Write([]byte("line\n")); err != nil {
+		t.Fatal(err)
+	}
+	matches, _ := filepath.Glob(dir + "/*/*/*/test.log")
+	if len(matches) != 1 || matches[0] != w2.Path() {
+		t.Errorf("left %v", matches)
+	}
+}
+
+func TestWriterRetentionTrailingConversion(t *testing.T) {
+	dir := t.TempDir()
+	setNow(t, time.Date(2022, 8, 10, 10, 0, 0, 0, time.Local))
+
+	// the rolled app.log.20220810.N are matched by both globs of the scan
+	w, err := NewWriter(dir+"/app.log.%Y%m%d", WithMaxSize(5), WithMaxFiles(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 8; i++ {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, _ := filepath.Glob(dir + "/app.log.*")
+	if len(matches) != 4 {
+		t.Errorf("kept %v, want 4 files", matches)
+	}
+}
+
+func TestWriterCompress(t *testing.T) {
+	dir := t.TempDir()
+	setNow(t, time.Date(2022, 8, 15, 10, 0, 0, 0, time.Local))
+
+	w, err := NewWriter(dir+"/test-%d.log", WithMaxSize(8), WithCompress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("first\n"))
+	w.Write([]byte("second\n")) // rolled to test-15.log.1
+	setNow(t, time.Date(2022, 8, 16, 10, 0, 0, 0, time.Local))
+	w.Write([]byte("third\n")) // rotated to test-16.log
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"test-15.log.1.gz": "first\n",
+		"test-15.log.gz":   "second\n",
+	} {
+		f, err := os.Open(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(zr)
+		f.Close()
+		if string(b) != want {
+			t.Errorf("%s: got %q, want %q", path, b, want)
+		}
+	}
+	for _, path := range []string{"test-15.log", "test-15.log.1"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); !os.IsNotExist(err) {
+			t.Errorf("%s not removed", path)
+		}
+	}
+}
+
+func TestWriterCompressRetention(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(dir+"/app.log", WithMaxSize(1000), WithCompress(), WithMaxFiles(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := bytes.Repeat([]byte("x"), 999)
+	for i := 0; i < 20; i++ {
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the current file and the 3 newest rolled ones, all compressed
+	matches, _ := filepath.Glob(dir + "/app.log*")
+	if len(matches) != 4 {
+		t.Fatalf("kept %v, want 4 files", matches)
+	}
+	for _, path := range matches {
+		if path != dir+"/app.log" && !strings.HasSuffix(path, ".gz") {
+			t.Errorf("%s not compressed", path)
+		}
+	}
+}
+
+func TestWriterAsync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	w, err := NewWriter(path, WithAsync(16, OverflowBlock), WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		w.Write([]byte("line\n"))
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(path)
+	if len(b) != 500 {
+		t.Errorf("flushed %d bytes, want 500", len(b))
+	}
+
+	w.Write([]byte("last\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(path)
+	if len(b) != 505 {
+		t.Errorf("closed with %d bytes, want 505", len(b))
+	}
+	if _, err := w.Write([]byte("closed\n")); err != os.ErrClosed {
+		t.Errorf("write after close: %v", err)
+	}
+}
+
+func TestWriterOverflow(t *testing.T) {
+	for _, overflow := range []Overflow{OverflowDropNewest, OverflowDropOldest} {
+		path := filepath.Join(t.TempDir(), "test.log")
+		w, err := NewWriter(path, WithAsync(1, overflow))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// hold the lock so the background goroutine can't drain the queue
+		w.mux.Lock()
+		for i := 0; i < 10; i++ {
+			w.Write([]byte("line\n"))
+		}
+		w.mux.Unlock()
+		w.Close()
+
+		// one message may have been taken by the goroutine before it blocked
+		if d := w.Dropped(); d < 8 || d > 9 {
+			t.Errorf("overflow %d: dropped %d", overflow, d)
+		}
+		b, _ := os.ReadFile(path)
+		if uint64(len(b)) != (10-w.Dropped())*5 {
+			t.Errorf("overflow %d: wrote %d bytes, dropped %d", overflow, len(b), w.Dropped())
+		}
+	}
+}
+
+func TestWriterOptions(t *testing.T) {
+	dir := t.TempDir()
+	setNow(t, time.Date(2022, 8, 15, 23, 30, 0, 0, time.FixedZone("CST", 8*3600)))
+
+	w, err := NewWriter(dir+"/%Y%m%d/%H.log",
+		WithSymlink(dir+"/current.log"),
+		WithLocation(time.UTC),
+		WithoutLock(),
+		WithInit(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// opened by NewWriter, formatted in UTC
+	want := filepath.Join(dir, "20220815", "15.log")
+	if got := w.Path(); got != want {
+		t.Errorf("path %s, want %s", got, want)
+	}
+	if got, err := os.Readlink(dir + "/current.log"); err != nil || got != want {
+		t.Errorf("symlink %s, %v", got, err)
+	}
+
+	if _, err := NewWriter(dir+"/test.log", WithSymlink("%")); err == nil {
+		t.Error("bad symlink pattern accepted")
+	}
+	if _, err := NewWriter(dir+"/test.log", WithAsync(8, OverflowBlock), WithoutLock()); err == nil {
+		t.Error("WithoutLock accepted with WithAsync")
+	}
+	for _, size := range []int{0, -1} {
+		if _, err := NewWriter(dir+"/test.log", WithAsync(size, OverflowDropOldest)); err == nil {
+			t.Errorf("async queue size %d accepted", size)
+		}
+	}
+}